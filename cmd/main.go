@@ -1,69 +1,474 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/HaroldoFV/product-service/configs"
 	_ "github.com/HaroldoFV/product-service/docs"
+	"github.com/HaroldoFV/product-service/internal/domain"
 	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/infra/events"
+	"github.com/HaroldoFV/product-service/internal/infra/graph"
+	"github.com/HaroldoFV/product-service/internal/infra/logging"
+	"github.com/HaroldoFV/product-service/internal/infra/metrics"
+	"github.com/HaroldoFV/product-service/internal/infra/rpc"
+	"github.com/HaroldoFV/product-service/internal/infra/tracing"
 	"github.com/HaroldoFV/product-service/internal/infra/web"
 	"github.com/HaroldoFV/product-service/internal/infra/web/webserver"
+	"github.com/HaroldoFV/product-service/internal/infra/webhook"
 	"github.com/HaroldoFV/product-service/internal/usecase"
 	_ "github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
 )
 
+// Exit codes of the service binary.
+const (
+	exitOK = 0
+	// exitError reports a failure to start, or a server that stopped on its own.
+	exitError = 1
+	// exitShutdownTimeout reports in-flight requests still running when the grace period ended.
+	exitShutdownTimeout = 2
+)
+
+// webhookLeaseMargin is added to the webhook timeout to lease the deliveries being sent, so that a
+// delivery is never claimed again while its request may still be running.
+const webhookLeaseMargin = 30 * time.Second
+
+// outboxLease keeps the events being published by the relay from being claimed again. A batch is
+// expected to be published well within it.
+const outboxLease = time.Minute
+
+// tracingFlushTimeout bounds the time spent sending the last spans once the server has stopped.
+const tracingFlushTimeout = 5 * time.Second
+
 // @title Product Service API
 // @version 1.0
 // @description This is a product microservice API.
 // @host localhost:8000
 // @BasePath /api/v1
 func main() {
+	os.Exit(run())
+}
+
+// run starts the service and blocks until it stops, returning the process exit code.
+// Deferred cleanups, such as closing the database pool, run before the process exits.
+func run() int {
 	dir, _ := os.Getwd()
-	fmt.Println("Diretório atual:", dir)
 
 	config, err := configs.LoadConfig(dir)
 	if err != nil {
 		rootDir := filepath.Join(dir, "..", "..")
 		config, err = configs.LoadConfig(rootDir)
 		if err != nil {
-			fmt.Println("Erro ao carregar configurações:", err)
-			panic(err)
+			// The configuration selects the log format, so fall back to the default one
+			logger, _ := logging.NewLogger(os.Stdout, "", "")
+			logger.Error("Erro ao carregar configurações", "dir", dir, "error", err)
+			return exitError
 		}
 	}
-	fmt.Printf("Configurações carregadas: %+v\n", config)
 
-	db, err := sql.Open(config.DBDriver, fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		config.DBHost, config.DBPort, config.DBUser, config.DBPassword, config.DBName))
+	logger, err := logging.NewLogger(os.Stdout, config.LogFormat, config.LogLevel)
 	if err != nil {
-		panic(err)
+		fmt.Fprintln(os.Stderr, "Erro ao configurar logs:", err)
+		return exitError
+	}
+	slog.SetDefault(logger)
+	logger.Info("configuration loaded", "config", config)
+
+	dataSourceName := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		config.DBHost, config.DBPort, config.DBUser, config.DBPassword, config.DBName)
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(logger, config.DBDriver, dataSourceName, os.Args[2:]); err != nil {
+			logger.Error("migration failed", "error", err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:     config.TracingExporter,
+		ServiceName:  config.ServiceName,
+		OTLPEndpoint: config.OTLPEndpoint,
+		OTLPInsecure: config.OTLPInsecure,
+		SampleRatio:  config.TracingSampleRatio,
+		Stdout:       os.Stdout,
+	})
+	if err != nil {
+		logger.Error("error setting up tracing", "error", err)
+		return exitError
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("error flushing traces", "error", err)
+		}
+	}()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	var productRepository domain.ProductRepositoryInterface
+	var auditRepository domain.ProductAuditRepositoryInterface
+	var outboxRepository domain.OutboxRepositoryInterface
+	var webhookRepository domain.WebhookRepositoryInterface
+	var transactionManager domain.TransactionManager
+	var healthChecks []web.HealthCheck
+	if config.DBDriver == "memory" {
+		logger.Info("using in-memory product repository")
+		productRepository = database.NewInMemoryProductRepository()
+		auditRepository = database.NewInMemoryProductAuditRepository()
+		outboxRepository = database.NewInMemoryProductOutboxRepository()
+		webhookRepository = database.NewInMemoryWebhookRepository()
+		transactionManager = database.NewInMemoryTransactionManager()
+	} else {
+		db, err := database.Open(config.DBDriver, dataSourceName)
+		if err != nil {
+			logger.Error("error opening database", "error", err)
+			return exitError
+		}
+		defer func() {
+			logger.Info("closing database connections")
+			db.Close()
+		}()
+		productRepository = database.NewProductRepository(db, logger)
+		auditRepository = database.NewProductAuditRepository(db)
+		outboxRepository = database.NewProductOutboxRepository(db)
+		webhookRepository = database.NewWebhookRepository(db)
+		transactionManager = database.NewTransactionManager(db)
+		registry.MustRegister(collectors.NewDBStatsCollector(db, config.DBName))
+
+		migrator, err := database.NewMigrator(db)
+		if err != nil {
+			logger.Error("error loading migrations", "error", err)
+			return exitError
+		}
+		healthChecks = append(healthChecks,
+			web.HealthCheck{Name: "database", Check: db.PingContext},
+			web.HealthCheck{Name: "migrations", Check: migrator.CheckVersion},
+		)
+
+		// The service still starts when the database is down; /readyz reports it until it is back
+		pingCtx, cancel := context.WithTimeout(context.Background(), config.HealthCheckTimeout)
+		if err := db.PingContext(pingCtx); err != nil {
+			logger.Warn("database is not reachable yet", "error", err)
+		}
+		cancel()
 	}
-	defer db.Close()
 
-	webServer := webserver.NewWebServer(":" + config.WebServerPort)
+	productRepository = metrics.NewInstrumentedProductRepository(productRepository, registry)
 
-	productRepository := database.NewProductRepository(db)
-	createProductUseCase := usecase.NewCreateProductUseCase(productRepository)
-	webProductHandler := web.NewWebProductHandler(createProductUseCase, productRepository)
+	webServer := webserver.NewWebServer(":"+config.WebServerPort, logger)
+	webServer.HTTPMetrics = metrics.NewHTTPMetrics(registry)
+	webServer.RequestTimeout = config.RequestTimeout
+	webServer.ReadTimeout = config.ReadTimeout
+	webServer.WriteTimeout = config.WriteTimeout
+	webServer.IdleTimeout = config.IdleTimeout
+
+	createProductUseCase := usecase.NewCreateProductUseCase(productRepository, auditRepository, outboxRepository, transactionManager, logger)
+	cursorSecret := []byte(config.CursorSecret)
+	if len(cursorSecret) == 0 {
+		logger.Warn("CURSOR_SECRET not set, using a random secret: cursors will not survive restarts")
+		cursorSecret = make([]byte, 32)
+		if _, err := rand.Read(cursorSecret); err != nil {
+			logger.Error("error generating cursor secret", "error", err)
+			return exitError
+		}
+	}
+	cursorCodec := usecase.NewCursorCodec(cursorSecret)
+	webProductHandler := web.NewWebProductHandler(createProductUseCase, productRepository, auditRepository, outboxRepository, transactionManager, cursorCodec, logger)
+	webProductHandler.ExportWriteTimeout = config.WriteTimeout
+	webProductHandler.RegisterRoutes(webServer)
+	webWebhookHandler := web.NewWebWebhookHandler(webhookRepository, logger)
+	webWebhookHandler.RegisterRoutes(webServer)
+	graphResolver := graph.NewResolver(productRepository, auditRepository, outboxRepository, transactionManager, logger)
+	webGraphQLHandler := web.NewWebGraphQLHandler(graphResolver, config.GraphQLComplexityLimit)
+	webGraphQLHandler.RegisterRoutes(webServer)
+
+	healthHandler := web.NewWebHealthHandler(config.HealthCheckTimeout, healthChecks...)
+	webServer.AddRootHandler(http.MethodGet, "/healthz", healthHandler.Liveness)
+	webServer.AddRootHandler(http.MethodGet, "/readyz", healthHandler.Readiness)
+	webServer.AddRootHandler(http.MethodGet, "/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP)
 
-	webServer.AddHandler(http.MethodPost, "/products", webProductHandler.Create)
-	webServer.AddHandler(http.MethodGet, "/products", webProductHandler.GetProducts)
-	webServer.AddHandler(http.MethodPut, "/products/{id}", webProductHandler.Update)
-	webServer.AddHandler(http.MethodGet, "/products/{id}", webProductHandler.GetProduct)
-	webServer.AddHandler(http.MethodDelete, "/products/{id}", webProductHandler.Delete)
 	webServer.AddHandler(http.MethodGet, "/docs/*", httpSwagger.Handler(
 		httpSwagger.URL("http://localhost:"+config.WebServerPort+"/docs/doc.json"),
 	))
 
-	fmt.Println("Starting web server on port", config.WebServerPort)
+	// The purge stops before the database pool is closed
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	purgeDone := make(chan struct{})
 	go func() {
-		err = webServer.Start()
-		if err != nil {
-			panic(err)
+		defer close(purgeDone)
+		purgeDeletedProducts(purgeCtx, logger, usecase.NewPurgeDeletedProductsUseCase(productRepository, auditRepository, outboxRepository, transactionManager, logger),
+			config.TrashRetention, config.TrashPurgeInterval)
+	}()
+	defer func() {
+		stopPurge()
+		<-purgeDone
+	}()
+
+	publisher, closePublisher, err := events.NewPublisher(config.EventPublisher, config.EventFile, logger)
+	if err != nil {
+		logger.Error("error creating event publisher", "error", err)
+		return exitError
+	}
+	defer closePublisher()
+	// Webhook deliveries are created as events are published, in a transaction of their own
+	enqueueWebhookDeliveries := usecase.NewEnqueueWebhookDeliveriesUseCase(webhookRepository, transactionManager)
+	publisher = events.MultiPublisher{publisher, events.PublisherFunc(enqueueWebhookDeliveries.Execute)}
+
+	// The relay stops before the publisher and the database pool are closed
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		relayProductEvents(relayCtx, logger, usecase.NewPublishProductEventsUseCase(outboxRepository, publisher, config.OutboxMaxAttempts, outboxLease, logger),
+			config.OutboxBatchSize, config.OutboxPollInterval)
+	}()
+	defer func() {
+		stopRelay()
+		<-relayDone
+	}()
+
+	// The dispatcher stops before the database pool is closed
+	dispatchCtx, stopDispatch := context.WithCancel(context.Background())
+	dispatchDone := make(chan struct{})
+	go func() {
+		defer close(dispatchDone)
+		retryPolicy := usecase.WebhookRetryPolicy{
+			MaxAttempts: config.WebhookMaxAttempts,
+			BaseDelay:   config.WebhookRetryDelay,
+			MaxDelay:    config.WebhookMaxDelay,
 		}
+		dispatch := usecase.NewDispatchWebhookDeliveriesUseCase(webhookRepository, webhook.NewHTTPSender(config.WebhookTimeout),
+			retryPolicy, config.WebhookTimeout+webhookLeaseMargin, logger)
+		dispatchWebhookDeliveries(dispatchCtx, logger, dispatch, config.WebhookBatchSize, config.WebhookInterval)
+	}()
+	defer func() {
+		stopDispatch()
+		<-dispatchDone
 	}()
-	select {}
+
+	productServer := rpc.NewProductServer(productRepository, auditRepository, outboxRepository, transactionManager, logger)
+	grpcServer := rpc.NewServer(":"+config.GRPCServerPort, productServer, logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info("starting web server", "port", config.WebServerPort)
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- webServer.Start()
+	}()
+
+	logger.Info("starting gRPC server", "port", config.GRPCServerPort)
+	grpcServerErr := make(chan error, 1)
+	go func() {
+		grpcServerErr <- grpcServer.Start()
+	}()
+
+	select {
+	case err := <-serverErr:
+		logger.Error("web server stopped unexpectedly", "error", err)
+		return exitError
+	case err := <-grpcServerErr:
+		logger.Error("gRPC server stopped unexpectedly", "error", err)
+		return exitError
+	case <-ctx.Done():
+	}
+	// A second signal kills the process right away instead of waiting for the drain
+	stop()
+
+	// Keep serving with a failing readiness probe until load balancers stop sending traffic
+	healthHandler.SetShuttingDown()
+	grpcServer.SetShuttingDown()
+	if config.ShutdownDelay > 0 {
+		logger.Info("readiness is failing, waiting before draining requests", "delay", config.ShutdownDelay.String())
+		time.Sleep(config.ShutdownDelay)
+	}
+
+	logger.Info("shutting down, waiting for in-flight requests", "timeout", config.ShutdownTimeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+	// Both servers drain at the same time, within the same grace period
+	grpcShutdownDone := make(chan error, 1)
+	go func() {
+		grpcShutdownDone <- grpcServer.Shutdown(shutdownCtx)
+	}()
+	webShutdownErr := webServer.Shutdown(shutdownCtx)
+	if webShutdownErr != nil {
+		logger.Error("error shutting down web server", "error", webShutdownErr)
+	}
+	grpcShutdownErr := <-grpcShutdownDone
+	if grpcShutdownErr != nil {
+		logger.Error("error shutting down gRPC server", "error", grpcShutdownErr)
+	}
+	if err := errors.Join(webShutdownErr, grpcShutdownErr); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return exitShutdownTimeout
+		}
+		return exitError
+	}
+	if err := <-serverErr; err != nil {
+		logger.Error("web server stopped with error", "error", err)
+		return exitError
+	}
+	if err := <-grpcServerErr; err != nil {
+		logger.Error("gRPC server stopped with error", "error", err)
+		return exitError
+	}
+	logger.Info("web and gRPC servers stopped")
+	return exitOK
+}
+
+// purgeDeletedProducts removes the products kept in the trash for longer than retention right
+// away and then every interval, until ctx is canceled. A zero interval disables the purge.
+func purgeDeletedProducts(ctx context.Context, logger *slog.Logger, purge *usecase.PurgeDeletedProductsUseCase, retention, interval time.Duration) {
+	if interval <= 0 {
+		logger.Info("purge of deleted products is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := purge.Execute(ctx, retention); err != nil && ctx.Err() == nil {
+			logger.Error("error purging deleted products", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relayProductEvents publishes the events written to the outbox, up to batchSize at a time, every
+// interval until ctx is canceled. Full batches are followed by the next one right away, so that a
+// backlog is drained without waiting. A zero interval disables the relay.
+func relayProductEvents(ctx context.Context, logger *slog.Logger, publish *usecase.PublishProductEventsUseCase, batchSize int, interval time.Duration) {
+	if interval <= 0 {
+		logger.Info("publication of product events is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		published, err := publish.Execute(ctx, batchSize)
+		if err != nil && ctx.Err() == nil {
+			logger.Error("error publishing product events", "error", err)
+		}
+		if err == nil && published == batchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatchWebhookDeliveries sends the due webhook deliveries, up to batchSize at a time, every
+// interval until ctx is canceled. Like the relay, it goes on right away after a full batch. A zero
+// interval disables the webhooks.
+func dispatchWebhookDeliveries(ctx context.Context, logger *slog.Logger, dispatch *usecase.DispatchWebhookDeliveriesUseCase, batchSize int, interval time.Duration) {
+	if interval <= 0 {
+		logger.Info("dispatch of webhook deliveries is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		attempted, err := dispatch.Execute(ctx, batchSize)
+		if err != nil && ctx.Err() == nil {
+			logger.Error("error dispatching webhook deliveries", "error", err)
+		}
+		if err == nil && attempted == batchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runMigrate implements "migrate up", "migrate down [steps]" and "migrate status" using the
+// migrations embedded in the binary. down reverts a single migration unless told otherwise.
+func runMigrate(logger *slog.Logger, driver, dataSourceName string, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up | down [steps|all] | status")
+	}
+	if driver == "memory" {
+		return errors.New("migrations require a database, DB_DRIVER is memory")
+	}
+
+	db, err := sql.Open(driver, dataSourceName)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		logger.Info("migrations applied", "count", applied)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if args[1] == "all" {
+				steps = 0
+			} else if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(steps)
+		logger.Info("migrations reverted", "count", reverted)
+		return err
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Dirty {
+				state = "dirty"
+			} else if status.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%06d %-40s %s\n", status.Version, status.Name, state)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down or status", args[0])
+	}
 }
@@ -2,23 +2,109 @@ package configs
 
 import (
 	"fmt"
+	"github.com/HaroldoFV/product-service/internal/infra/logging"
 	"github.com/spf13/viper"
+	"log/slog"
 	"path/filepath"
+	"time"
 )
 
 type conf struct {
-	DBDriver      string `mapstructure:"DB_DRIVER"`
-	DBHost        string `mapstructure:"DB_HOST"`
-	DBPort        string `mapstructure:"DB_PORT"`
-	DBUser        string `mapstructure:"DB_USER"`
-	DBPassword    string `mapstructure:"DB_PASSWORD"`
-	DBName        string `mapstructure:"DB_NAME"`
-	WebServerPort string `mapstructure:"WEB_SERVER_PORT"`
+	DBDriver           string        `mapstructure:"DB_DRIVER"`
+	DBHost             string        `mapstructure:"DB_HOST"`
+	DBPort             string        `mapstructure:"DB_PORT"`
+	DBUser             string        `mapstructure:"DB_USER"`
+	DBPassword         string        `mapstructure:"DB_PASSWORD"`
+	DBName             string        `mapstructure:"DB_NAME"`
+	WebServerPort      string        `mapstructure:"WEB_SERVER_PORT"`
+	GRPCServerPort     string        `mapstructure:"GRPC_SERVER_PORT"`
+	CursorSecret       string        `mapstructure:"CURSOR_SECRET"`
+	RequestTimeout     time.Duration `mapstructure:"REQUEST_TIMEOUT"`
+	ReadTimeout        time.Duration `mapstructure:"READ_TIMEOUT"`
+	WriteTimeout       time.Duration `mapstructure:"WRITE_TIMEOUT"`
+	IdleTimeout        time.Duration `mapstructure:"IDLE_TIMEOUT"`
+	ShutdownTimeout    time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	ShutdownDelay      time.Duration `mapstructure:"SHUTDOWN_DELAY"`
+	HealthCheckTimeout time.Duration `mapstructure:"HEALTH_CHECK_TIMEOUT"`
+	LogFormat          string        `mapstructure:"LOG_FORMAT"`
+	LogLevel           string        `mapstructure:"LOG_LEVEL"`
+	TracingExporter    string        `mapstructure:"TRACING_EXPORTER"`
+	TracingSampleRatio float64       `mapstructure:"TRACING_SAMPLE_RATIO"`
+	ServiceName        string        `mapstructure:"SERVICE_NAME"`
+	OTLPEndpoint       string        `mapstructure:"OTLP_ENDPOINT"`
+	OTLPInsecure       bool          `mapstructure:"OTLP_INSECURE"`
+	TrashRetention     time.Duration `mapstructure:"TRASH_RETENTION"`
+	TrashPurgeInterval time.Duration `mapstructure:"TRASH_PURGE_INTERVAL"`
+	EventPublisher     string        `mapstructure:"EVENT_PUBLISHER"`
+	EventFile          string        `mapstructure:"EVENT_FILE"`
+	OutboxPollInterval time.Duration `mapstructure:"OUTBOX_POLL_INTERVAL"`
+	OutboxBatchSize    int           `mapstructure:"OUTBOX_BATCH_SIZE"`
+	OutboxMaxAttempts  int           `mapstructure:"OUTBOX_MAX_ATTEMPTS"`
+	WebhookInterval    time.Duration `mapstructure:"WEBHOOK_DISPATCH_INTERVAL"`
+	WebhookBatchSize   int           `mapstructure:"WEBHOOK_BATCH_SIZE"`
+	WebhookTimeout     time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`
+	WebhookMaxAttempts int           `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookRetryDelay  time.Duration `mapstructure:"WEBHOOK_RETRY_DELAY"`
+	WebhookMaxDelay    time.Duration `mapstructure:"WEBHOOK_MAX_RETRY_DELAY"`
+	// GraphQLComplexityLimit rejects the GraphQL queries above this complexity. Zero disables the limit.
+	GraphQLComplexityLimit int `mapstructure:"GRAPHQL_COMPLEXITY_LIMIT"`
+	// ConfigFile is the path of the .env file the configuration was read from.
+	ConfigFile string `mapstructure:"-"`
 }
 
-func LoadConfig(path string) (*conf, error) {
-	fmt.Println("Tentando carregar configurações do diretório:", path)
+// LogValue lets the configuration be logged without exposing the database password or the cursor secret.
+func (c conf) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("config_file", c.ConfigFile),
+		slog.String("db_driver", c.DBDriver),
+		slog.String("db_host", c.DBHost),
+		slog.String("db_port", c.DBPort),
+		slog.String("db_user", c.DBUser),
+		slog.String("db_password", redacted(c.DBPassword)),
+		slog.String("db_name", c.DBName),
+		slog.String("web_server_port", c.WebServerPort),
+		slog.String("grpc_server_port", c.GRPCServerPort),
+		slog.String("cursor_secret", redacted(c.CursorSecret)),
+		slog.String("request_timeout", c.RequestTimeout.String()),
+		slog.String("read_timeout", c.ReadTimeout.String()),
+		slog.String("write_timeout", c.WriteTimeout.String()),
+		slog.String("idle_timeout", c.IdleTimeout.String()),
+		slog.String("shutdown_timeout", c.ShutdownTimeout.String()),
+		slog.String("shutdown_delay", c.ShutdownDelay.String()),
+		slog.String("health_check_timeout", c.HealthCheckTimeout.String()),
+		slog.String("log_format", c.LogFormat),
+		slog.String("log_level", c.LogLevel),
+		slog.String("tracing_exporter", c.TracingExporter),
+		slog.Float64("tracing_sample_ratio", c.TracingSampleRatio),
+		slog.String("service_name", c.ServiceName),
+		slog.String("otlp_endpoint", c.OTLPEndpoint),
+		slog.Bool("otlp_insecure", c.OTLPInsecure),
+		slog.String("trash_retention", c.TrashRetention.String()),
+		slog.String("trash_purge_interval", c.TrashPurgeInterval.String()),
+		slog.String("event_publisher", c.EventPublisher),
+		slog.String("event_file", c.EventFile),
+		slog.String("outbox_poll_interval", c.OutboxPollInterval.String()),
+		slog.Int("outbox_batch_size", c.OutboxBatchSize),
+		slog.Int("outbox_max_attempts", c.OutboxMaxAttempts),
+		slog.String("webhook_dispatch_interval", c.WebhookInterval.String()),
+		slog.Int("webhook_batch_size", c.WebhookBatchSize),
+		slog.String("webhook_timeout", c.WebhookTimeout.String()),
+		slog.Int("webhook_max_attempts", c.WebhookMaxAttempts),
+		slog.String("webhook_retry_delay", c.WebhookRetryDelay.String()),
+		slog.String("webhook_max_retry_delay", c.WebhookMaxDelay.String()),
+		slog.Int("graphql_complexity_limit", c.GraphQLComplexityLimit),
+	)
+}
+
+// redacted hides secret, keeping only whether it is set.
+func redacted(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return logging.RedactedValue
+}
 
+func LoadConfig(path string) (*conf, error) {
 	viper.SetConfigName(".env")
 	viper.SetConfigType("env")
 	viper.AddConfigPath(path)
@@ -26,6 +112,36 @@ func LoadConfig(path string) (*conf, error) {
 	viper.AddConfigPath(filepath.Join(path, "..", "..")) // Diretório avô
 	viper.AddConfigPath("/")                             // Raiz do sistema de arquivos
 	viper.AutomaticEnv()
+	viper.SetDefault("GRPC_SERVER_PORT", "50051")
+	viper.SetDefault("CURSOR_SECRET", "")
+	viper.SetDefault("REQUEST_TIMEOUT", "30s")
+	viper.SetDefault("READ_TIMEOUT", "15s")
+	viper.SetDefault("WRITE_TIMEOUT", "60s")
+	viper.SetDefault("IDLE_TIMEOUT", "120s")
+	viper.SetDefault("SHUTDOWN_TIMEOUT", "25s")
+	viper.SetDefault("SHUTDOWN_DELAY", "0s")
+	viper.SetDefault("HEALTH_CHECK_TIMEOUT", "2s")
+	viper.SetDefault("LOG_FORMAT", "json")
+	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("TRACING_EXPORTER", "none")
+	viper.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+	viper.SetDefault("SERVICE_NAME", "product-service")
+	viper.SetDefault("OTLP_ENDPOINT", "localhost:4317")
+	viper.SetDefault("OTLP_INSECURE", true)
+	viper.SetDefault("TRASH_RETENTION", "720h")
+	viper.SetDefault("TRASH_PURGE_INTERVAL", "1h")
+	viper.SetDefault("EVENT_PUBLISHER", "log")
+	viper.SetDefault("EVENT_FILE", "product-events.ndjson")
+	viper.SetDefault("OUTBOX_POLL_INTERVAL", "1s")
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 100)
+	viper.SetDefault("OUTBOX_MAX_ATTEMPTS", 10)
+	viper.SetDefault("WEBHOOK_DISPATCH_INTERVAL", "1s")
+	viper.SetDefault("WEBHOOK_BATCH_SIZE", 50)
+	viper.SetDefault("WEBHOOK_TIMEOUT", "10s")
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	viper.SetDefault("WEBHOOK_RETRY_DELAY", "30s")
+	viper.SetDefault("WEBHOOK_MAX_RETRY_DELAY", "1h")
+	viper.SetDefault("GRAPHQL_COMPLEXITY_LIMIT", 1000)
 
 	err := viper.ReadInConfig()
 	if err != nil {
@@ -38,8 +154,7 @@ func LoadConfig(path string) (*conf, error) {
 		return nil, fmt.Errorf("erro ao decodificar configurações: %w", err)
 	}
 
-	fmt.Printf("Arquivo de configuração usado: %s\n", viper.ConfigFileUsed())
-	fmt.Printf("Configurações carregadas: %+v\n", config)
+	config.ConfigFile = viper.ConfigFileUsed()
 
 	return &config, nil
 }
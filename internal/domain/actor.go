@@ -0,0 +1,26 @@
+package domain
+
+import "context"
+
+// Actors recorded when the caller does not identify itself.
+const (
+	// SystemActor makes the changes not triggered by a request, such as purging the trash.
+	SystemActor = "system"
+	// AnonymousActor makes the changes requested without an actor.
+	AnonymousActor = "anonymous"
+)
+
+type actorContextKey struct{}
+
+// WithActor returns a copy of ctx carrying who is making the changes, recorded in the audit trail.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored by WithActor, or SystemActor when there is none.
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorContextKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+
+	domain "github.com/HaroldoFV/product-service/internal/domain/entity"
+)
+
+// ProductCursor is a position in a product listing ordered by Sort. Values holds the
+// fields of the product at that position, one for each key of Sort.WithTiebreaker().
+// A Backward cursor selects the products before the position instead of after it.
+type ProductCursor struct {
+	Sort     ProductSort
+	Values   []string
+	Backward bool
+}
+
+// NewProductCursor returns the position of product in a listing ordered by sort.
+func NewProductCursor(product *domain.Product, sort ProductSort, backward bool) ProductCursor {
+	keys := sort.WithTiebreaker()
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = ProductSortValue(product, key.Field)
+	}
+	return ProductCursor{Sort: sort, Values: values, Backward: backward}
+}
+
+// ProductSortValue formats the value of a sortable field of product.
+func ProductSortValue(product *domain.Product, field string) string {
+	switch field {
+	case SortByName:
+		return product.GetName()
+	case SortByPrice:
+		return strconv.FormatFloat(product.GetPrice(), 'f', -1, 64)
+	case SortByStatus:
+		return product.GetStatus()
+	case SortByCreatedAt:
+		return product.GetCreatedAt().UTC().Format(time.RFC3339Nano)
+	default:
+		return product.GetID()
+	}
+}
@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Operations recorded in ProductAuditEntry.Operation.
+const (
+	AuditCreate            = "create"
+	AuditUpdate            = "update"
+	AuditPatch             = "patch"
+	AuditEnable            = "enable"
+	AuditDisable           = "disable"
+	AuditDelete            = "delete"
+	AuditRestore           = "restore"
+	AuditDeletePermanently = "delete_permanently"
+	AuditPurge             = "purge"
+)
+
+// Fields compared by DiffProducts. AuditFieldDeleted tells whether the product is in the trash.
+const (
+	AuditFieldName        = "name"
+	AuditFieldDescription = "description"
+	AuditFieldPrice       = "price"
+	AuditFieldStatus      = "status"
+	AuditFieldDeleted     = "deleted"
+)
+
+// FieldChange is the value of a field before and after a change. A nil Before means the product
+// was created, a nil After that it was removed for good.
+type FieldChange struct {
+	Field  string
+	Before any
+	After  any
+}
+
+// ProductAuditEntry records a change made to a product. Entries are never modified once stored.
+type ProductAuditEntry struct {
+	ID        string
+	ProductID string
+	// Actor is who made the change, as identified by the caller.
+	Actor     string
+	Operation string
+	// Version is the version of the product once changed, or its last version when it was removed.
+	Version   int
+	Changes   []FieldChange
+	CreatedAt time.Time
+}
+
+func NewProductAuditEntry(productID, actor, operation string, version int, changes []FieldChange) *ProductAuditEntry {
+	return &ProductAuditEntry{
+		ID:        uuid.New().String(),
+		ProductID: productID,
+		Actor:     actor,
+		Operation: operation,
+		Version:   version,
+		Changes:   changes,
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+}
+
+// DiffProducts lists the fields that differ between before and after, in a fixed order. Every
+// field is listed when either product is nil.
+func DiffProducts(before, after *Product) []FieldChange {
+	var changes []FieldChange
+	for _, field := range []string{AuditFieldName, AuditFieldDescription, AuditFieldPrice, AuditFieldStatus, AuditFieldDeleted} {
+		change := FieldChange{Field: field}
+		if before != nil {
+			change.Before = auditValue(before, field)
+		}
+		if after != nil {
+			change.After = auditValue(after, field)
+		}
+		if change.Before != change.After {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func auditValue(product *Product, field string) any {
+	switch field {
+	case AuditFieldName:
+		return product.GetName()
+	case AuditFieldDescription:
+		return product.GetDescription()
+	case AuditFieldPrice:
+		return product.GetPrice()
+	case AuditFieldStatus:
+		return product.GetStatus()
+	default:
+		return product.IsDeleted()
+	}
+}
@@ -0,0 +1,53 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffProducts(t *testing.T) {
+	product, err := entity.NewProduct("Product", "description", 10)
+	require.NoError(t, err)
+
+	t.Run("Created product", func(t *testing.T) {
+		require.Equal(t, []entity.FieldChange{
+			{Field: entity.AuditFieldName, After: "Product"},
+			{Field: entity.AuditFieldDescription, After: "description"},
+			{Field: entity.AuditFieldPrice, After: 10.0},
+			{Field: entity.AuditFieldStatus, After: entity.DISABLED},
+			{Field: entity.AuditFieldDeleted, After: false},
+		}, entity.DiffProducts(nil, product))
+	})
+
+	t.Run("Changed fields only", func(t *testing.T) {
+		changed := *product
+		require.NoError(t, changed.ChangePrice(12.5))
+		require.NoError(t, changed.Enable())
+		require.Equal(t, []entity.FieldChange{
+			{Field: entity.AuditFieldPrice, Before: 10.0, After: 12.5},
+			{Field: entity.AuditFieldStatus, Before: entity.DISABLED, After: entity.ENABLED},
+		}, entity.DiffProducts(product, &changed))
+	})
+
+	t.Run("No change", func(t *testing.T) {
+		require.Empty(t, entity.DiffProducts(product, product))
+	})
+
+	t.Run("Removed product", func(t *testing.T) {
+		changes := entity.DiffProducts(product, nil)
+		require.Len(t, changes, 5)
+		require.Equal(t, entity.FieldChange{Field: entity.AuditFieldName, Before: "Product"}, changes[0])
+	})
+}
+
+func TestNewProductAuditEntry(t *testing.T) {
+	entry := entity.NewProductAuditEntry("product-id", "alice", entity.AuditUpdate, 2, nil)
+	require.NotEmpty(t, entry.ID)
+	require.Equal(t, "product-id", entry.ProductID)
+	require.Equal(t, "alice", entry.Actor)
+	require.Equal(t, entity.AuditUpdate, entry.Operation)
+	require.Equal(t, 2, entry.Version)
+	require.False(t, entry.CreatedAt.IsZero())
+}
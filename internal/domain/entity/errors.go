@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors shared by the domain, use case and infrastructure layers.
+// Callers should compare with errors.Is instead of matching error messages.
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrValidation         = errors.New("validation failed")
+	ErrConflict           = errors.New("conflict")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrInvalidInput       = errors.New("invalid input")
+)
+
+// ValidationError reports a business rule violated by a single field.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func NewValidationError(field, message string) *ValidationError {
+	return &ValidationError{Field: field, Message: message}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// ProductNotFoundError is returned by repositories when no product matches the given id.
+type ProductNotFoundError struct {
+	ID string
+}
+
+func NewProductNotFoundError(id string) *ProductNotFoundError {
+	return &ProductNotFoundError{ID: id}
+}
+
+func (e *ProductNotFoundError) Error() string {
+	return fmt.Sprintf("product with id %s not found", e.ID)
+}
+
+func (e *ProductNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// ProductVersionConflictError is returned by repositories when a product was modified since
+// it was read, so that writing it would overwrite someone else's changes.
+type ProductVersionConflictError struct {
+	ID      string
+	Version int
+}
+
+func NewProductVersionConflictError(id string, version int) *ProductVersionConflictError {
+	return &ProductVersionConflictError{ID: id, Version: version}
+}
+
+func (e *ProductVersionConflictError) Error() string {
+	return fmt.Sprintf("product with id %s was modified since version %d", e.ID, e.Version)
+}
+
+func (e *ProductVersionConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Types of the events raised by Product.
+const (
+	ProductCreated  = "product.created"
+	ProductUpdated  = "product.updated"
+	PriceChanged    = "product.price_changed"
+	ProductEnabled  = "product.enabled"
+	ProductDisabled = "product.disabled"
+	ProductDeleted  = "product.deleted"
+	ProductRestored = "product.restored"
+)
+
+// ProductEvent tells other services about a change made to a product.
+type ProductEvent struct {
+	ID        string
+	Type      string
+	ProductID string
+	// Version is the version of the product once changed, or its last version when it was removed.
+	Version    int
+	OccurredAt time.Time
+	// Data describes the change: the old and new price for PriceChanged, whether the product was
+	// removed for good for ProductDeleted, and the product as left by the change otherwise.
+	Data map[string]any
+}
+
+// record raises an event of the given type. Its version is only known once the product is stored.
+func (p *Product) record(eventType string, data map[string]any) {
+	p.events = append(p.events, ProductEvent{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		ProductID:  p.id,
+		OccurredAt: time.Now().UTC().Truncate(time.Microsecond),
+		Data:       data,
+	})
+}
+
+// recordState raises an event carrying the product as it is now.
+func (p *Product) recordState(eventType string) {
+	p.record(eventType, map[string]any{
+		"name":        p.name,
+		"description": p.description,
+		"price":       p.price,
+		"status":      p.status,
+	})
+}
+
+// RecordDeleted raises ProductDeleted for a product the repository moved to the trash or, with
+// permanent set, removed for good.
+func (p *Product) RecordDeleted(permanent bool) {
+	p.record(ProductDeleted, map[string]any{"permanent": permanent})
+}
+
+// RecordRestored raises ProductRestored for a product the repository took out of the trash.
+func (p *Product) RecordRestored() {
+	p.recordState(ProductRestored)
+}
+
+// PullEvents returns the events raised since the last call, in the order they were raised, and
+// forgets them. It must be called once the product is stored, so that the events carry the
+// version the product was stored at.
+func (p *Product) PullEvents() []*ProductEvent {
+	events := make([]*ProductEvent, len(p.events))
+	for i := range p.events {
+		event := p.events[i]
+		event.Version = p.version
+		events[i] = &event
+	}
+	p.events = nil
+	return events
+}
+
+// ClearEvents forgets the events raised so far without returning them.
+func (p *Product) ClearEvents() {
+	p.events = nil
+}
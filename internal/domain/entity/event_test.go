@@ -0,0 +1,81 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func eventTypes(events []*entity.ProductEvent) []string {
+	types := make([]string, len(events))
+	for i, event := range events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+func TestProductEvents(t *testing.T) {
+	product, err := entity.NewProduct("Product", "description", 10)
+	require.NoError(t, err)
+
+	events := product.PullEvents()
+	require.Len(t, events, 1)
+	created := events[0]
+	require.Equal(t, entity.ProductCreated, created.Type)
+	require.Equal(t, product.GetID(), created.ProductID)
+	require.Equal(t, 1, created.Version)
+	require.NotEmpty(t, created.ID)
+	require.False(t, created.OccurredAt.IsZero())
+	require.Equal(t, map[string]any{"name": "Product", "description": "description", "price": 10.0, "status": entity.DISABLED}, created.Data)
+	require.Empty(t, product.PullEvents(), "events are returned once")
+
+	t.Run("Only actual changes raise events", func(t *testing.T) {
+		require.NoError(t, product.Update("Product", "description"))
+		require.NoError(t, product.ChangePrice(10))
+		require.NoError(t, product.Disable())
+		require.Empty(t, product.PullEvents())
+	})
+
+	t.Run("Changes", func(t *testing.T) {
+		require.NoError(t, product.Update("Renamed", "description"))
+		require.NoError(t, product.ChangePrice(12.5))
+		require.NoError(t, product.Enable())
+		product.SetVersion(2)
+
+		events := product.PullEvents()
+		require.Equal(t, []string{entity.ProductUpdated, entity.PriceChanged, entity.ProductEnabled}, eventTypes(events))
+		for _, event := range events {
+			require.Equal(t, 2, event.Version, "events carry the version the product was stored at")
+		}
+		require.Equal(t, "Renamed", events[0].Data["name"])
+		require.Equal(t, map[string]any{"old_price": 10.0, "new_price": 12.5}, events[1].Data)
+		require.Equal(t, entity.ENABLED, events[2].Data["status"])
+
+		require.NoError(t, product.Disable())
+		require.Equal(t, []string{entity.ProductDisabled}, eventTypes(product.PullEvents()))
+	})
+
+	t.Run("Invalid changes raise no event", func(t *testing.T) {
+		require.Error(t, product.ChangePrice(-1))
+		require.Error(t, product.Update("", "description"))
+		require.Empty(t, product.PullEvents())
+	})
+
+	t.Run("Changes made by the repository", func(t *testing.T) {
+		product.RecordDeleted(false)
+		product.RecordRestored()
+		product.RecordDeleted(true)
+		events := product.PullEvents()
+		require.Equal(t, []string{entity.ProductDeleted, entity.ProductRestored, entity.ProductDeleted}, eventTypes(events))
+		require.Equal(t, false, events[0].Data["permanent"])
+		require.Equal(t, true, events[2].Data["permanent"])
+	})
+
+	t.Run("Cleared events", func(t *testing.T) {
+		product, err := entity.NewProduct("Product", "description", 10)
+		require.NoError(t, err)
+		product.ClearEvents()
+		require.Empty(t, product.PullEvents())
+	})
+}
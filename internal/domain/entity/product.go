@@ -1,7 +1,9 @@
 package entity
 
 import (
-	"errors"
+	"math"
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -16,6 +18,14 @@ type Product struct {
 	description string
 	price       float64
 	status      string
+	createdAt   time.Time
+	// version starts at 1 and is incremented by repositories on every update, so that
+	// concurrent writers can detect each other.
+	version int
+	// deletedAt is set while the product is in the trash and zero otherwise.
+	deletedAt time.Time
+	// events raised by the changes made since the product was created or read, see PullEvents.
+	events []ProductEvent
 }
 
 func NewProduct(name, description string, price float64) (*Product, error) {
@@ -25,6 +35,29 @@ func NewProduct(name, description string, price float64) (*Product, error) {
 		description: description,
 		price:       price,
 		status:      DISABLED,
+		createdAt:   time.Now().UTC().Truncate(time.Microsecond),
+		version:     1,
+	}
+	err := product.IsValid()
+	if err != nil {
+		return nil, err
+	}
+	product.recordState(ProductCreated)
+	return product, nil
+}
+
+// RehydrateProduct rebuilds a product that was already stored, e.g. by a repository reading
+// a row. Unlike NewProduct it records no event, since nothing changed.
+func RehydrateProduct(id, name, description string, price float64, status string, createdAt time.Time, version int, deletedAt time.Time) (*Product, error) {
+	product := &Product{
+		id:          id,
+		name:        name,
+		description: description,
+		price:       price,
+		status:      status,
+		createdAt:   createdAt,
+		version:     version,
+		deletedAt:   deletedAt,
 	}
 	err := product.IsValid()
 	if err != nil {
@@ -34,63 +67,86 @@ func NewProduct(name, description string, price float64) (*Product, error) {
 }
 
 func (p *Product) Update(name, description string) error {
+	changed := name != p.name || description != p.description
 	p.name = name
 	p.description = description
-	return p.IsValid()
+	err := p.IsValid()
+	if err != nil {
+		return err
+	}
+	if changed {
+		p.recordState(ProductUpdated)
+	}
+	return nil
 }
 
 func (p *Product) IsValid() error {
 	if p.id == "" {
-		return errors.New("invalid id")
+		return NewValidationError("id", "invalid id")
 	}
 	if p.name == "" {
-		return errors.New("name cannot be empty")
+		return NewValidationError("name", "name cannot be empty")
 	}
 	if len(p.name) > 100 {
-		return errors.New("name cannot be longer than 100 characters")
+		return NewValidationError("name", "name cannot be longer than 100 characters")
 	}
 	if len(p.description) > 500 {
-		return errors.New("description cannot be longer than 500 characters")
+		return NewValidationError("description", "description cannot be longer than 500 characters")
 	}
 	if p.status == "" {
 		p.status = DISABLED
 	}
 	if p.status != ENABLED && p.status != DISABLED {
-		return errors.New("status must be enabled or disabled")
+		return NewValidationError("status", "status must be enabled or disabled")
+	}
+	if math.IsNaN(p.price) || math.IsInf(p.price, 0) {
+		return NewValidationError("price", "price must be a finite number")
 	}
 	if p.price < 0 {
-		return errors.New("price must be greater or equal zero")
+		return NewValidationError("price", "price must be greater or equal zero")
 	}
 	return nil
 }
 
 func (p *Product) Enable() error {
-	if p.price > 0 {
-		p.status = ENABLED
-		return nil
+	if p.price <= 0 {
+		return NewValidationError("price", "price must be greater than zero to enable the product")
 	}
+	changed := p.status != ENABLED
+	p.status = ENABLED
 	err := p.IsValid()
 	if err != nil {
 		return err
 	}
+	if changed {
+		p.recordState(ProductEnabled)
+	}
 	return nil
 }
 
 func (p *Product) Disable() error {
+	changed := p.status != DISABLED
 	p.status = DISABLED
 	err := p.IsValid()
 	if err != nil {
 		return err
 	}
+	if changed {
+		p.recordState(ProductDisabled)
+	}
 	return nil
 }
 
 func (p *Product) ChangePrice(price float64) error {
+	oldPrice := p.price
 	p.price = price
 	err := p.IsValid()
 	if err != nil {
 		return err
 	}
+	if price != oldPrice {
+		p.record(PriceChanged, map[string]any{"old_price": oldPrice, "new_price": price})
+	}
 	return nil
 }
 
@@ -114,6 +170,39 @@ func (p *Product) GetPrice() float64 {
 	return p.price
 }
 
+func (p *Product) GetCreatedAt() time.Time {
+	return p.createdAt
+}
+
+func (p *Product) GetVersion() int {
+	return p.version
+}
+
+func (p *Product) GetDeletedAt() time.Time {
+	return p.deletedAt
+}
+
+// IsDeleted reports whether the product is in the trash.
+func (p *Product) IsDeleted() bool {
+	return !p.deletedAt.IsZero()
+}
+
 func (p *Product) SetID(id string) {
 	p.id = id
 }
+
+func (p *Product) SetStatus(status string) {
+	p.status = status
+}
+
+func (p *Product) SetCreatedAt(createdAt time.Time) {
+	p.createdAt = createdAt
+}
+
+func (p *Product) SetVersion(version int) {
+	p.version = version
+}
+
+func (p *Product) SetDeletedAt(deletedAt time.Time) {
+	p.deletedAt = deletedAt
+}
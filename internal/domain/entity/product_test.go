@@ -3,7 +3,9 @@ package entity_test
 import (
 	entity "github.com/HaroldoFV/product-service/internal/domain/entity"
 	"github.com/stretchr/testify/require"
+	"math"
 	"testing"
+	"time"
 )
 
 func TestNewProduct(t *testing.T) {
@@ -14,6 +16,7 @@ func TestNewProduct(t *testing.T) {
 		require.Equal(t, "Product 1", product.GetName())
 		require.Equal(t, "description", product.GetDescription())
 		require.Equal(t, entity.DISABLED, product.GetStatus())
+		require.Equal(t, 1, product.GetVersion())
 	})
 
 	t.Run("Invalid Name", func(t *testing.T) {
@@ -39,6 +42,30 @@ func TestNewProduct(t *testing.T) {
 	})
 }
 
+func TestRehydrateProduct(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	deletedAt := createdAt.Add(time.Hour)
+
+	t.Run("Valid Product", func(t *testing.T) {
+		product, err := entity.RehydrateProduct("0b9d6a8e-2c4f-4a3b-9d1e-5f6a7b8c9d0e", "Product 1", "description", 99.99, entity.ENABLED, createdAt, 3, deletedAt)
+		require.NoError(t, err)
+		require.Equal(t, "0b9d6a8e-2c4f-4a3b-9d1e-5f6a7b8c9d0e", product.GetID())
+		require.Equal(t, "Product 1", product.GetName())
+		require.Equal(t, "description", product.GetDescription())
+		require.Equal(t, 99.99, product.GetPrice())
+		require.Equal(t, entity.ENABLED, product.GetStatus())
+		require.Equal(t, createdAt, product.GetCreatedAt())
+		require.Equal(t, 3, product.GetVersion())
+		require.Equal(t, deletedAt, product.GetDeletedAt())
+		require.Empty(t, product.PullEvents(), "reading a stored product changes nothing")
+	})
+
+	t.Run("Invalid Product", func(t *testing.T) {
+		_, err := entity.RehydrateProduct("0b9d6a8e-2c4f-4a3b-9d1e-5f6a7b8c9d0e", "Product 1", "description", 99.99, "archived", createdAt, 1, time.Time{})
+		require.EqualError(t, err, "status must be enabled or disabled")
+	})
+}
+
 func TestProduct_Enable(t *testing.T) {
 	t.Run("Enable with Valid Price", func(t *testing.T) {
 		product, _ := entity.NewProduct("Product 1", "Product 1 description", 99.90)
@@ -46,6 +73,14 @@ func TestProduct_Enable(t *testing.T) {
 		require.Nil(t, err)
 		require.Equal(t, entity.ENABLED, product.GetStatus())
 	})
+
+	t.Run("Enable with Zero Price", func(t *testing.T) {
+		product, _ := entity.NewProduct("Product 1", "Product 1 description", 0)
+		err := product.Enable()
+		require.EqualError(t, err, "price must be greater than zero to enable the product")
+		require.ErrorIs(t, err, entity.ErrValidation)
+		require.Equal(t, entity.DISABLED, product.GetStatus())
+	})
 }
 
 func TestProduct_Disable(t *testing.T) {
@@ -137,3 +172,29 @@ func TestProduct_Update(t *testing.T) {
 		require.Equal(t, entity.ENABLED, product.GetStatus())
 	})
 }
+
+func TestProduct_ValidationErrors(t *testing.T) {
+	t.Run("Validation error exposes field", func(t *testing.T) {
+		_, err := entity.NewProduct("Product 1", "description", -1)
+		require.ErrorIs(t, err, entity.ErrValidation)
+
+		var validationErr *entity.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Equal(t, "price", validationErr.Field)
+	})
+
+	t.Run("Price must be finite", func(t *testing.T) {
+		for _, price := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+			_, err := entity.NewProduct("Product 1", "description", price)
+			require.ErrorIs(t, err, entity.ErrValidation)
+			require.EqualError(t, err, "price must be a finite number")
+		}
+	})
+
+	t.Run("Not found error", func(t *testing.T) {
+		err := entity.NewProductNotFoundError("123")
+		require.ErrorIs(t, err, entity.ErrNotFound)
+		require.NotErrorIs(t, err, entity.ErrValidation)
+		require.EqualError(t, err, "product with id 123 not found")
+	})
+}
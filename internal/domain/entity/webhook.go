@@ -0,0 +1,135 @@
+package entity
+
+import (
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Statuses of a WebhookDelivery.
+const (
+	// DeliveryPending deliveries are waiting for their next attempt.
+	DeliveryPending = "pending"
+	// DeliveryDelivered deliveries were accepted by the receiver.
+	DeliveryDelivered = "delivered"
+	// DeliveryDead deliveries failed every attempt and are only retried on request.
+	DeliveryDead = "dead"
+)
+
+// minWebhookSecretLength keeps the signatures of webhooks hard to forge.
+const minWebhookSecretLength = 16
+
+// ProductEventTypes lists the types of the events raised by Product.
+var ProductEventTypes = []string{
+	ProductCreated, ProductUpdated, PriceChanged, ProductEnabled, ProductDisabled, ProductDeleted, ProductRestored,
+}
+
+// Webhook subscribes an HTTP endpoint to product events.
+type Webhook struct {
+	ID  string
+	URL string
+	// Events are the types of the events delivered to the webhook, every type when empty.
+	Events []string
+	// Secret signs the deliveries, so that the receiver can tell they come from the service.
+	Secret    string
+	CreatedAt time.Time
+}
+
+func NewWebhook(targetURL string, events []string, secret string) (*Webhook, error) {
+	webhook := &Webhook{
+		ID:        uuid.New().String(),
+		URL:       targetURL,
+		Events:    events,
+		Secret:    secret,
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+	err := webhook.IsValid()
+	if err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (w *Webhook) IsValid() error {
+	target, err := url.Parse(w.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return NewValidationError("url", "url must be an absolute http or https URL")
+	}
+	for _, event := range w.Events {
+		if !slices.Contains(ProductEventTypes, event) {
+			return NewValidationError("events", "unknown event type "+event)
+		}
+	}
+	if len(w.Secret) < minWebhookSecretLength {
+		return NewValidationError("secret", "secret must be at least 16 characters long")
+	}
+	return nil
+}
+
+// Matches reports whether events of the given type are delivered to the webhook.
+func (w *Webhook) Matches(eventType string) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, eventType)
+}
+
+// WebhookDelivery is the delivery of an event to a webhook, along with the outcome of its last
+// attempt.
+type WebhookDelivery struct {
+	ID        string
+	WebhookID string
+	Event     ProductEvent
+	Status    string
+	// Attempts counts the attempts made since the delivery was created or last retried.
+	Attempts      int
+	NextAttemptAt time.Time
+	// LastStatusCode is the HTTP status of the last response, zero when no response was received.
+	LastStatusCode int
+	LastError      string
+	CreatedAt      time.Time
+	// DeliveredAt is zero until the delivery succeeds.
+	DeliveredAt time.Time
+}
+
+// NewWebhookDelivery returns a delivery of event to the webhook, due right away.
+func NewWebhookDelivery(webhookID string, event *ProductEvent) *WebhookDelivery {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	return &WebhookDelivery{
+		ID:            uuid.New().String(),
+		WebhookID:     webhookID,
+		Event:         *event,
+		Status:        DeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+// Succeeded records an attempt accepted by the receiver with the given HTTP status.
+func (d *WebhookDelivery) Succeeded(statusCode int, at time.Time) {
+	d.Attempts++
+	d.Status = DeliveryDelivered
+	d.LastStatusCode = statusCode
+	d.LastError = ""
+	d.DeliveredAt = at
+}
+
+// Failed records a failed attempt, with the HTTP status received if any. The delivery is tried
+// again at nextAttemptAt, or becomes dead when retry is false.
+func (d *WebhookDelivery) Failed(statusCode int, reason string, retry bool, nextAttemptAt time.Time) {
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = reason
+	if retry {
+		d.Status = DeliveryPending
+		d.NextAttemptAt = nextAttemptAt
+	} else {
+		d.Status = DeliveryDead
+	}
+}
+
+// Retry makes a delivery due right away, with a new series of attempts.
+func (d *WebhookDelivery) Retry(at time.Time) {
+	d.Status = DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = at
+}
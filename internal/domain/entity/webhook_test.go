@@ -0,0 +1,86 @@
+package entity_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/stretchr/testify/require"
+)
+
+const webhookSecret = "0123456789abcdef"
+
+func TestNewWebhook(t *testing.T) {
+	webhook, err := entity.NewWebhook("https://example.com/hooks", []string{entity.PriceChanged}, webhookSecret)
+	require.NoError(t, err)
+	require.NotEmpty(t, webhook.ID)
+	require.False(t, webhook.CreatedAt.IsZero())
+	require.True(t, webhook.Matches(entity.PriceChanged))
+	require.False(t, webhook.Matches(entity.ProductCreated))
+
+	webhook, err = entity.NewWebhook("http://localhost:8080", nil, webhookSecret)
+	require.NoError(t, err)
+	require.True(t, webhook.Matches(entity.ProductDeleted), "every event type when no type is given")
+}
+
+func TestWebhookIsValid(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		events []string
+		secret string
+		field  string
+	}{
+		{"Relative URL", "/hooks", nil, webhookSecret, "url"},
+		{"Unsupported scheme", "ftp://example.com/hooks", nil, webhookSecret, "url"},
+		{"Missing host", "https://", nil, webhookSecret, "url"},
+		{"Unknown event type", "https://example.com", []string{"product.sold"}, webhookSecret, "events"},
+		{"Short secret", "https://example.com", nil, "secret", "secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := entity.NewWebhook(tt.url, tt.events, tt.secret)
+			require.ErrorIs(t, err, entity.ErrValidation)
+			var validationErr *entity.ValidationError
+			require.True(t, errors.As(err, &validationErr))
+			require.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	product, err := entity.NewProduct("Product", "description", 10)
+	require.NoError(t, err)
+	event := product.PullEvents()[0]
+
+	delivery := entity.NewWebhookDelivery("webhook", event)
+	require.Equal(t, entity.DeliveryPending, delivery.Status)
+	require.Equal(t, *event, delivery.Event)
+	require.Equal(t, delivery.CreatedAt, delivery.NextAttemptAt, "due right away")
+
+	next := time.Now().Add(time.Minute)
+	delivery.Failed(500, "unexpected status 500", true, next)
+	require.Equal(t, entity.DeliveryPending, delivery.Status)
+	require.Equal(t, 1, delivery.Attempts)
+	require.Equal(t, next, delivery.NextAttemptAt)
+	require.Equal(t, 500, delivery.LastStatusCode)
+
+	delivery.Failed(0, "connection refused", false, next.Add(time.Minute))
+	require.Equal(t, entity.DeliveryDead, delivery.Status)
+	require.Equal(t, 2, delivery.Attempts)
+	require.Equal(t, 0, delivery.LastStatusCode)
+	require.Equal(t, "connection refused", delivery.LastError)
+
+	now := time.Now()
+	delivery.Retry(now)
+	require.Equal(t, entity.DeliveryPending, delivery.Status)
+	require.Zero(t, delivery.Attempts)
+	require.Equal(t, now, delivery.NextAttemptAt)
+
+	delivery.Succeeded(204, now)
+	require.Equal(t, entity.DeliveryDelivered, delivery.Status)
+	require.Equal(t, 1, delivery.Attempts)
+	require.Empty(t, delivery.LastError)
+	require.Equal(t, now, delivery.DeliveredAt)
+}
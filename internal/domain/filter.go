@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"fmt"
+
+	domain "github.com/HaroldoFV/product-service/internal/domain/entity"
+)
+
+// ProductFilter narrows the products returned by List and ForEach. Zero values mean "no filter".
+type ProductFilter struct {
+	Status     string
+	MinPrice   *float64
+	MaxPrice   *float64
+	NamePrefix string
+	// Query is a full-text search over name and description. Every term must match.
+	Query string
+}
+
+func (f ProductFilter) Validate() error {
+	if f.Status != "" && f.Status != domain.ENABLED && f.Status != domain.DISABLED {
+		return fmt.Errorf("%w: status must be %s or %s", domain.ErrInvalidInput, domain.ENABLED, domain.DISABLED)
+	}
+	if f.MinPrice != nil && *f.MinPrice < 0 {
+		return fmt.Errorf("%w: min_price must be greater or equal zero", domain.ErrInvalidInput)
+	}
+	if f.MaxPrice != nil && *f.MaxPrice < 0 {
+		return fmt.Errorf("%w: max_price must be greater or equal zero", domain.ErrInvalidInput)
+	}
+	if f.MinPrice != nil && f.MaxPrice != nil && *f.MinPrice > *f.MaxPrice {
+		return fmt.Errorf("%w: min_price cannot be greater than max_price", domain.ErrInvalidInput)
+	}
+	return nil
+}
@@ -1,11 +1,121 @@
 package domain
 
-import domain "github.com/HaroldoFV/product-service/internal/domain/entity"
+import (
+	"context"
+	"time"
 
+	domain "github.com/HaroldoFV/product-service/internal/domain/entity"
+)
+
+// ProductRepositoryInterface stores products. Every method stops its work as soon as ctx is
+// canceled or its deadline expires. Deleted products stay in the trash until restored or purged
+// and are ignored by every method except ListDeleted, Restore, DeletePermanently and PurgeDeleted.
 type ProductRepositoryInterface interface {
-	Create(product *domain.Product) error
-	Update(product *domain.Product) error
-	GetByID(id string) (*domain.Product, error)
-	List(page, limit int, sort string) ([]*domain.Product, int, error)
-	Delete(id string) error
+	Create(ctx context.Context, product *domain.Product) error
+	CreateBatch(ctx context.Context, products []*domain.Product) error
+	// Update stores product only if it is still at the version it was read with, incrementing
+	// the version of product on success. Otherwise it returns a ProductVersionConflictError.
+	Update(ctx context.Context, product *domain.Product) error
+	GetByID(ctx context.Context, id string) (*domain.Product, error)
+	// GetByIDs returns the products with the given ids in a single round trip, in no particular
+	// order. Products that do not exist or are in the trash are left out rather than reported.
+	GetByIDs(ctx context.Context, ids []string) ([]*domain.Product, error)
+	// List returns a page of the products matching filter along with the total number of matches.
+	// Ties are broken by id.
+	List(ctx context.Context, filter ProductFilter, page, limit int, sort ProductSort) ([]*domain.Product, int, error)
+	// ListByCursor returns up to limit products matching filter that come after cursor in the
+	// listing ordered by sort, or before it when cursor.Backward is set. A nil cursor starts at
+	// the beginning. Products are always returned in listing order. Ties are broken by id.
+	ListByCursor(ctx context.Context, filter ProductFilter, sort ProductSort, cursor *ProductCursor, limit int) ([]*domain.Product, error)
+	// ForEach calls fn for every product matching filter, ordered by id, without loading the
+	// whole catalog into memory. Iteration stops at the first error.
+	ForEach(ctx context.Context, filter ProductFilter, fn func(*domain.Product) error) error
+	// Delete moves the product to the trash, incrementing its version, and returns it as left in
+	// the trash. A product already in the trash is not found.
+	Delete(ctx context.Context, id string) (*domain.Product, error)
+	// Restore takes a product out of the trash, incrementing its version, and returns it. A
+	// product that is not in the trash is reported as a conflict.
+	Restore(ctx context.Context, id string) (*domain.Product, error)
+	// ListDeleted returns a page of the products in the trash, most recently deleted first,
+	// along with the total number of products in the trash.
+	ListDeleted(ctx context.Context, page, limit int) ([]*domain.Product, int, error)
+	// DeletePermanently removes the product for good, whether it is in the trash or not, and
+	// returns it as it was last stored.
+	DeletePermanently(ctx context.Context, id string) (*domain.Product, error)
+	// PurgeDeleted permanently removes the products moved to the trash before deletedBefore and
+	// returns them as they were last stored.
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) ([]*domain.Product, error)
+}
+
+// ProductAuditRepositoryInterface stores the audit trail of products. Entries can only be appended.
+type ProductAuditRepositoryInterface interface {
+	Append(ctx context.Context, entries ...*domain.ProductAuditEntry) error
+	// ListByProduct returns a page of the entries of a product, the last appended first, along
+	// with the total number of entries of the product. The trail outlives the product itself.
+	ListByProduct(ctx context.Context, productID string, page, limit int) ([]*domain.ProductAuditEntry, int, error)
+}
+
+// TransactionManager runs several repository calls atomically.
+type TransactionManager interface {
+	// WithinTransaction calls fn with a context bound to a new transaction, committed when fn
+	// returns nil and rolled back otherwise. Repositories called with that context take part in
+	// the transaction, and so do nested calls to WithinTransaction.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// OutboxRepositoryInterface stores the events raised by products until they are published.
+// Events are appended in the same transaction as the changes that raised them, so that both
+// are stored or neither is.
+type OutboxRepositoryInterface interface {
+	Append(ctx context.Context, events ...*domain.ProductEvent) error
+	// ClaimPending returns up to limit events not published yet, in the order they were appended,
+	// and claims them until now plus lease, so that concurrent callers skip them while they are
+	// being published. The claim ends with MarkPublished, MarkFailed or Release.
+	ClaimPending(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.ProductEvent, error)
+	MarkPublished(ctx context.Context, ids ...string) error
+	// MarkFailed records a failed attempt to publish an event, which stays pending until it has
+	// failed maxAttempts times. It then becomes dead: ClaimPending no longer returns it, and dead
+	// reports so.
+	MarkFailed(ctx context.Context, id string, reason string, maxAttempts int) (dead bool, err error)
+	// Release ends the claim on events that were not attempted, so that they can be claimed again
+	// right away.
+	Release(ctx context.Context, ids ...string) error
+}
+
+// EventPublisher delivers product events to other services.
+type EventPublisher interface {
+	Publish(ctx context.Context, event *domain.ProductEvent) error
+}
+
+// WebhookDeliveryFilter narrows ListDeliveries. Empty fields match every delivery.
+type WebhookDeliveryFilter struct {
+	WebhookID string
+	Status    string
+}
+
+// WebhookRepositoryInterface stores webhooks and their deliveries.
+type WebhookRepositoryInterface interface {
+	Create(ctx context.Context, webhook *domain.Webhook) error
+	GetByID(ctx context.Context, id string) (*domain.Webhook, error)
+	// List returns every webhook, oldest first.
+	List(ctx context.Context) ([]*domain.Webhook, error)
+	// Delete removes the webhook along with its deliveries.
+	Delete(ctx context.Context, id string) error
+	CreateDeliveries(ctx context.Context, deliveries ...*domain.WebhookDelivery) error
+	GetDelivery(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+	// ClaimDueDeliveries returns up to limit pending deliveries due at now, oldest first, and
+	// postpones their next attempt until now plus lease, so that concurrent callers do not claim
+	// them again while they are being sent.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error)
+	// ListDeliveries returns a page of the deliveries matching filter, most recent first, along
+	// with the total number of matches.
+	ListDeliveries(ctx context.Context, filter WebhookDeliveryFilter, page, limit int) ([]*domain.WebhookDelivery, int, error)
+}
+
+// WebhookSender sends deliveries to webhooks.
+type WebhookSender interface {
+	// Send posts delivery to the webhook and returns the HTTP status of the response, or an error
+	// when none was received.
+	Send(ctx context.Context, webhook *domain.Webhook, delivery *domain.WebhookDelivery) (int, error)
 }
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	domain "github.com/HaroldoFV/product-service/internal/domain/entity"
+)
+
+// Fields products can be sorted by.
+const (
+	SortByID        = "id"
+	SortByName      = "name"
+	SortByPrice     = "price"
+	SortByStatus    = "status"
+	SortByCreatedAt = "created_at"
+)
+
+var validSortFields = map[string]bool{
+	SortByID:        true,
+	SortByName:      true,
+	SortByPrice:     true,
+	SortByStatus:    true,
+	SortByCreatedAt: true,
+}
+
+// SortField is a single sort key. Desc reverses its natural ascending order.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ProductSort is an ordered list of sort keys, the first one being the most significant.
+type ProductSort []SortField
+
+// ParseProductSort parses a comma separated list of fields, each optionally prefixed with
+// "-" for descending order, such as "-price,name". An empty string sorts by id.
+func ParseProductSort(s string) (ProductSort, error) {
+	if strings.TrimSpace(s) == "" {
+		return ProductSort{{Field: SortByID}}, nil
+	}
+
+	var result ProductSort
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		field := SortField{Field: part}
+		if strings.HasPrefix(part, "-") {
+			field = SortField{Field: part[1:], Desc: true}
+		} else if strings.HasPrefix(part, "+") {
+			field = SortField{Field: part[1:]}
+		}
+
+		if !validSortFields[field.Field] {
+			return nil, fmt.Errorf("%w: invalid sort field %q, valid fields are %s",
+				domain.ErrInvalidInput, field.Field, strings.Join(ValidSortFields(), ", "))
+		}
+		if seen[field.Field] {
+			return nil, fmt.Errorf("%w: sort field %q is repeated", domain.ErrInvalidInput, field.Field)
+		}
+		seen[field.Field] = true
+		result = append(result, field)
+	}
+	return result, nil
+}
+
+// ValidSortFields returns the sortable fields in alphabetical order.
+func ValidSortFields() []string {
+	fields := make([]string, 0, len(validSortFields))
+	for field := range validSortFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// WithTiebreaker returns the sort with id appended as the last key, unless it is already
+// present, so that every product has a unique position in the listing.
+func (s ProductSort) WithTiebreaker() ProductSort {
+	for _, field := range s {
+		if field.Field == SortByID {
+			return s
+		}
+	}
+	result := make(ProductSort, len(s), len(s)+1)
+	copy(result, s)
+	return append(result, SortField{Field: SortByID})
+}
+
+// String formats the sort back into the syntax accepted by ParseProductSort.
+func (s ProductSort) String() string {
+	parts := make([]string, len(s))
+	for i, field := range s {
+		parts[i] = field.Field
+		if field.Desc {
+			parts[i] = "-" + field.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
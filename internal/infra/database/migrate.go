@@ -0,0 +1,272 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/lib/pq"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migrationFileName matches files such as 000001_init.up.sql, the layout used by golang-migrate.
+var migrationFileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// migrationLockID identifies the advisory lock held while migrating, so that
+// several instances starting at once never apply the same migration twice.
+const migrationLockID = 7265341902
+
+// Migration is a versioned schema change with the SQL to apply and to revert it.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied to the database.
+type MigrationStatus struct {
+	Version uint
+	Name    string
+	Applied bool
+	Dirty   bool
+}
+
+// Migrator applies the migrations embedded in the binary. The current version is stored in a
+// schema_migrations table compatible with golang-migrate, so databases migrated with the
+// migrate/migrate image can be taken over without changes.
+type Migrator struct {
+	Db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationsFS)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{Db: db, migrations: migrations}, nil
+}
+
+// Migrations returns the embedded migrations ordered by version.
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Up applies every pending migration, each in its own transaction. It returns the number of
+// migrations applied.
+func (m *Migrator) Up() (int, error) {
+	applied := 0
+	for {
+		done, err := m.step(func(version uint) (*Migration, uint, error) {
+			for i := range m.migrations {
+				if m.migrations[i].Version > version {
+					return &m.migrations[i], m.migrations[i].Version, nil
+				}
+			}
+			return nil, 0, nil
+		}, true)
+		if err != nil || done {
+			return applied, err
+		}
+		applied++
+	}
+}
+
+// Down reverts the last steps applied migrations, or all of them when steps is not positive.
+// It returns the number of migrations reverted.
+func (m *Migrator) Down(steps int) (int, error) {
+	reverted := 0
+	for steps <= 0 || reverted < steps {
+		done, err := m.step(func(version uint) (*Migration, uint, error) {
+			if version == 0 {
+				return nil, 0, nil
+			}
+			for i := range m.migrations {
+				if m.migrations[i].Version == version {
+					previous := uint(0)
+					if i > 0 {
+						previous = m.migrations[i-1].Version
+					}
+					return &m.migrations[i], previous, nil
+				}
+			}
+			return nil, 0, fmt.Errorf("database is at version %d, which has no migration", version)
+		}, false)
+		if err != nil || done {
+			return reverted, err
+		}
+		reverted++
+	}
+	return reverted, nil
+}
+
+// Status lists every embedded migration and whether it has been applied.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	tx, err := m.Db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	version, dirty, err := currentVersion(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = MigrationStatus{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Applied: migration.Version <= version,
+			Dirty:   dirty && migration.Version == version,
+		}
+	}
+	return statuses, nil
+}
+
+// CheckVersion returns an error unless the database is clean and has every embedded migration
+// applied. A newer schema is accepted, since it is what an instance of the previous release sees
+// during a rolling deploy. It never modifies the database.
+func (m *Migrator) CheckVersion(ctx context.Context) error {
+	var version int64
+	var dirty bool
+	err := m.Db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	var pqErr *pq.Error
+	if errors.Is(err, sql.ErrNoRows) || (errors.As(err, &pqErr) && pqErr.Code == pqUndefinedTable) {
+		version = 0
+	} else if err != nil {
+		return err
+	}
+
+	if dirty {
+		return fmt.Errorf("database is dirty at version %d", version)
+	}
+	if latest := m.migrations[len(m.migrations)-1].Version; version < int64(latest) {
+		return fmt.Errorf("database is at version %d, expected %d", version, latest)
+	}
+	return nil
+}
+
+// step runs a single migration inside a transaction holding the migration lock. next picks the
+// migration to run given the current version, along with the version recorded once it has run;
+// a nil migration means there is nothing left to do.
+func (m *Migrator) step(next func(version uint) (*Migration, uint, error), up bool) (bool, error) {
+	tx, err := m.Db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec("SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+		return false, err
+	}
+	version, dirty, err := currentVersion(tx)
+	if err != nil {
+		return false, err
+	}
+	if dirty {
+		return false, fmt.Errorf("database is dirty at version %d, fix it manually and reset the version", version)
+	}
+
+	migration, target, err := next(version)
+	if err != nil || migration == nil {
+		return migration == nil && err == nil, err
+	}
+
+	query, direction := migration.Up, "up"
+	if !up {
+		query, direction = migration.Down, "down"
+	}
+	if _, err = tx.Exec(query); err != nil {
+		return false, fmt.Errorf("migration %d_%s %s failed: %w", migration.Version, migration.Name, direction, err)
+	}
+	if _, err = tx.Exec("DELETE FROM schema_migrations"); err != nil {
+		return false, err
+	}
+	if target > 0 {
+		if _, err = tx.Exec("INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)", target); err != nil {
+			return false, err
+		}
+	}
+	return false, tx.Commit()
+}
+
+// currentVersion returns the version recorded in schema_migrations, creating the table when
+// needed. Version 0 means no migration has been applied.
+func currentVersion(tx *sql.Tx) (uint, bool, error) {
+	_, err := tx.Exec("CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)")
+	if err != nil {
+		return 0, false, err
+	}
+
+	var version int64
+	var dirty bool
+	err = tx.QueryRow("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && version < 0) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return uint(version), dirty, nil
+}
+
+// loadMigrations reads pairs of up and down files from fsys, requiring both for every version.
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, file := range files {
+		name := file[len("migrations/"):]
+		match := migrationFileName.FindStringSubmatch(name)
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", name)
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", name)
+		}
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
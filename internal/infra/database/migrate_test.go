@@ -0,0 +1,22 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrator, err := database.NewMigrator(nil)
+	require.NoError(t, err)
+
+	migrations := migrator.Migrations()
+	require.NotEmpty(t, migrations)
+	for i, migration := range migrations {
+		require.Equal(t, uint(i+1), migration.Version, "migration versions must be consecutive")
+		require.NotEmpty(t, migration.Name)
+		require.NotEmpty(t, migration.Up)
+		require.NotEmpty(t, migration.Down)
+	}
+}
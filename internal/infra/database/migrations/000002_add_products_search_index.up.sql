@@ -0,0 +1,4 @@
+CREATE INDEX IF NOT EXISTS products_search_idx
+    ON products USING GIN (to_tsvector('simple', name || ' ' || coalesce(description, '')));
+
+CREATE INDEX IF NOT EXISTS products_status_idx ON products (status);
@@ -0,0 +1,3 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS products_deleted_at_idx ON products (deleted_at, id) WHERE deleted_at IS NOT NULL;
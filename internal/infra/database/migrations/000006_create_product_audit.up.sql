@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS product_audit
+(
+    id         UUID PRIMARY KEY,
+    -- Orders the entries as they were written, even within the same microsecond
+    seq        BIGINT GENERATED ALWAYS AS IDENTITY,
+    -- No foreign key: the trail outlives the products removed for good
+    product_id UUID        NOT NULL,
+    actor      TEXT        NOT NULL,
+    operation  VARCHAR(30) NOT NULL,
+    version    INTEGER     NOT NULL,
+    changes    JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS product_audit_product_idx ON product_audit (product_id, seq);
+
+-- Audit entries are append-only
+CREATE OR REPLACE FUNCTION product_audit_immutable() RETURNS trigger AS
+$$
+BEGIN
+    RAISE EXCEPTION 'product_audit entries cannot be modified';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS product_audit_immutable ON product_audit;
+CREATE TRIGGER product_audit_immutable
+    BEFORE UPDATE OR DELETE
+    ON product_audit
+    FOR EACH ROW
+EXECUTE FUNCTION product_audit_immutable();
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS product_outbox
+(
+    id           UUID PRIMARY KEY,
+    -- Orders the events as they were written, even within the same microsecond
+    seq          BIGINT GENERATED ALWAYS AS IDENTITY,
+    event_type   VARCHAR(50) NOT NULL,
+    product_id   UUID        NOT NULL,
+    version      INTEGER     NOT NULL,
+    data         JSONB       NOT NULL,
+    occurred_at  TIMESTAMPTZ NOT NULL,
+    published_at TIMESTAMPTZ,
+    attempts     INTEGER     NOT NULL DEFAULT 0,
+    last_error   TEXT
+);
+
+-- The relay only reads the events still to be published
+CREATE INDEX IF NOT EXISTS product_outbox_pending_idx ON product_outbox (seq) WHERE published_at IS NULL;
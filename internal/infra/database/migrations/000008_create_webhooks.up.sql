@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS webhooks
+(
+    id         UUID PRIMARY KEY,
+    url        TEXT        NOT NULL,
+    -- Event types delivered to the webhook, every type when empty
+    events     TEXT[]      NOT NULL,
+    secret     TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id               UUID PRIMARY KEY,
+    -- Orders the deliveries as they were created, even within the same microsecond
+    seq              BIGINT GENERATED ALWAYS AS IDENTITY,
+    webhook_id       UUID        NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event_id         UUID        NOT NULL,
+    event_type       VARCHAR(50) NOT NULL,
+    product_id       UUID        NOT NULL,
+    version          INTEGER     NOT NULL,
+    data             JSONB       NOT NULL,
+    occurred_at      TIMESTAMPTZ NOT NULL,
+    status           VARCHAR(20) NOT NULL,
+    attempts         INTEGER     NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMPTZ NOT NULL,
+    last_status_code INTEGER,
+    last_error       TEXT,
+    created_at       TIMESTAMPTZ NOT NULL,
+    delivered_at     TIMESTAMPTZ
+);
+
+-- The dispatcher only reads the deliveries waiting for an attempt
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_idx ON webhook_deliveries (webhook_id, seq);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_status_idx ON webhook_deliveries (status, seq);
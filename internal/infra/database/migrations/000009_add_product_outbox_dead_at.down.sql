@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS product_outbox_pending_idx;
+CREATE INDEX IF NOT EXISTS product_outbox_pending_idx ON product_outbox (seq) WHERE published_at IS NULL;
+
+-- Dead events become pending again
+ALTER TABLE product_outbox DROP COLUMN IF EXISTS dead_at;
@@ -0,0 +1,5 @@
+-- Events that failed every attempt are set aside so that the relay goes on with the next ones
+ALTER TABLE product_outbox ADD COLUMN IF NOT EXISTS dead_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS product_outbox_pending_idx;
+CREATE INDEX IF NOT EXISTS product_outbox_pending_idx ON product_outbox (seq) WHERE published_at IS NULL AND dead_at IS NULL;
@@ -0,0 +1 @@
+ALTER TABLE product_outbox DROP COLUMN IF EXISTS claimed_until;
@@ -0,0 +1,3 @@
+-- Relays claim the events they publish instead of locking them, so that no transaction stays open
+-- while the events are sent
+ALTER TABLE product_outbox ADD COLUMN IF NOT EXISTS claimed_until TIMESTAMPTZ;
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+)
+
+// InMemoryProductAuditRepository is a thread-safe, non-persistent implementation of
+// ProductAuditRepositoryInterface, the companion of InMemoryProductRepository.
+type InMemoryProductAuditRepository struct {
+	mu      sync.RWMutex
+	entries map[string][]entity.ProductAuditEntry
+}
+
+func NewInMemoryProductAuditRepository() *InMemoryProductAuditRepository {
+	return &InMemoryProductAuditRepository{
+		entries: make(map[string][]entity.ProductAuditEntry),
+	}
+}
+
+func (r *InMemoryProductAuditRepository) Append(ctx context.Context, entries ...*entity.ProductAuditEntry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, entry := range entries {
+		stored := *entry
+		stored.Changes = slices.Clone(entry.Changes)
+		r.entries[entry.ProductID] = append(r.entries[entry.ProductID], stored)
+	}
+	return nil
+}
+
+func (r *InMemoryProductAuditRepository) ListByProduct(ctx context.Context, productID string, page, limit int) ([]*entity.ProductAuditEntry, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// Newest first: entries are stored in the order they were appended
+	r.mu.RLock()
+	stored := r.entries[productID]
+	entries := make([]*entity.ProductAuditEntry, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		entry := stored[i]
+		entry.Changes = slices.Clone(stored[i].Changes)
+		entries = append(entries, &entry)
+	}
+	r.mu.RUnlock()
+
+	totalCount := len(entries)
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= totalCount {
+		return nil, totalCount, nil
+	}
+	end := offset + limit
+	if end > totalCount {
+		end = totalCount
+	}
+	return entries[offset:end], totalCount, nil
+}
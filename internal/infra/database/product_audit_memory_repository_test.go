@@ -0,0 +1,59 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryProductAuditRepository(t *testing.T) {
+	repository := database.NewInMemoryProductAuditRepository()
+	ctx := context.Background()
+
+	created := entity.NewProductAuditEntry("product-1", "alice", entity.AuditCreate, 1,
+		[]entity.FieldChange{{Field: entity.AuditFieldPrice, After: 10.0}})
+	updated := entity.NewProductAuditEntry("product-1", "bob", entity.AuditUpdate, 2,
+		[]entity.FieldChange{{Field: entity.AuditFieldPrice, Before: 10.0, After: 12.0}})
+	other := entity.NewProductAuditEntry("product-2", "alice", entity.AuditCreate, 1, nil)
+	require.NoError(t, repository.Append(ctx, created, updated))
+	require.NoError(t, repository.Append(ctx, other))
+
+	entries, totalCount, err := repository.ListByProduct(ctx, "product-1", 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, 2, totalCount)
+	require.Equal(t, []*entity.ProductAuditEntry{updated, created}, entries, "last appended first")
+
+	// Entries cannot be changed through the values given to Append or returned by ListByProduct
+	created.Changes[0].After = 99.0
+	entries[0].Changes[0].After = 99.0
+	entries, _, err = repository.ListByProduct(ctx, "product-1", 2, 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, 10.0, entries[0].Changes[0].After)
+
+	entries, totalCount, err = repository.ListByProduct(ctx, "missing", 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, 0, totalCount)
+	require.Empty(t, entries)
+}
+
+func TestInMemoryTransactionManager(t *testing.T) {
+	manager := database.NewInMemoryTransactionManager()
+	called := false
+	require.NoError(t, manager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		called = true
+		return nil
+	}))
+	require.True(t, called)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := manager.WithinTransaction(ctx, func(ctx context.Context) error {
+		t.Fatal("fn must not be called with a canceled context")
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
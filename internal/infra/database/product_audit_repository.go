@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+)
+
+// auditChange is the JSON representation of an entity.FieldChange in the changes column.
+type auditChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+type ProductAuditRepository struct {
+	Db *sql.DB
+}
+
+func NewProductAuditRepository(db *sql.DB) *ProductAuditRepository {
+	return &ProductAuditRepository{Db: db}
+}
+
+// Append inserts entries with multi-row INSERT statements of at most batchSize rows each, inside
+// the transaction in ctx if any.
+func (r *ProductAuditRepository) Append(ctx context.Context, entries ...*entity.ProductAuditEntry) error {
+	for start := 0; start < len(entries); start += batchSize {
+		end := start + batchSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		batch := entries[start:end]
+
+		var query strings.Builder
+		query.WriteString("INSERT INTO product_audit (id, product_id, actor, operation, version, changes, created_at) VALUES ")
+		args := make([]any, 0, len(batch)*7)
+		for i, entry := range batch {
+			changes := make([]auditChange, len(entry.Changes))
+			for j, change := range entry.Changes {
+				changes[j] = auditChange(change)
+			}
+			document, err := json.Marshal(changes)
+			if err != nil {
+				return err
+			}
+
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			n := i * 7
+			fmt.Fprintf(&query, "($%d, $%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7)
+			args = append(args, entry.ID, entry.ProductID, entry.Actor, entry.Operation, entry.Version, document, entry.CreatedAt)
+		}
+
+		_, err := conn(ctx, r.Db).ExecContext(ctx, query.String(), args...)
+		if err != nil {
+			return translateError("", err)
+		}
+	}
+	return nil
+}
+
+func (r *ProductAuditRepository) ListByProduct(ctx context.Context, productID string, page, limit int) ([]*entity.ProductAuditEntry, int, error) {
+	offset := (page - 1) * limit
+
+	var totalCount int
+	err := conn(ctx, r.Db).QueryRowContext(ctx, "SELECT COUNT(*) FROM product_audit WHERE product_id = $1", productID).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, translateError(productID, err)
+	}
+
+	query := "SELECT id, product_id, actor, operation, version, changes, created_at FROM product_audit " +
+		"WHERE product_id = $1 ORDER BY seq DESC LIMIT $2 OFFSET $3"
+	rows, err := conn(ctx, r.Db).QueryContext(ctx, query, productID, limit, offset)
+	if err != nil {
+		return nil, 0, translateError(productID, err)
+	}
+	defer rows.Close()
+
+	var entries []*entity.ProductAuditEntry
+	for rows.Next() {
+		var entry entity.ProductAuditEntry
+		var document []byte
+		err = rows.Scan(&entry.ID, &entry.ProductID, &entry.Actor, &entry.Operation, &entry.Version, &document, &entry.CreatedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var changes []auditChange
+		if err = json.Unmarshal(document, &changes); err != nil {
+			return nil, 0, err
+		}
+		for _, change := range changes {
+			entry.Changes = append(entry.Changes, entity.FieldChange(change))
+		}
+		entry.CreatedAt = entry.CreatedAt.UTC()
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return entries, totalCount, nil
+}
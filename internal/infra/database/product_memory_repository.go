@@ -0,0 +1,450 @@
+package database
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+)
+
+// InMemoryProductRepository is a thread-safe, non-persistent implementation of
+// ProductRepositoryInterface. It is meant for tests and local runs without Postgres.
+// Operations never block, so ctx is only checked before doing any work and between the
+// products visited by ForEach.
+type InMemoryProductRepository struct {
+	mu       sync.RWMutex
+	products map[string]entity.Product
+}
+
+func NewInMemoryProductRepository() *InMemoryProductRepository {
+	return &InMemoryProductRepository{
+		products: make(map[string]entity.Product),
+	}
+}
+
+func (r *InMemoryProductRepository) Create(ctx context.Context, product *entity.Product) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.products[product.GetID()]; ok {
+		return fmt.Errorf("%w: product with id %s already exists", entity.ErrConflict, product.GetID())
+	}
+	r.store(product)
+	return nil
+}
+
+// CreateBatch stores all products or none of them, mirroring the transactional insert of ProductRepository.
+func (r *InMemoryProductRepository) CreateBatch(ctx context.Context, products []*entity.Product) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]bool, len(products))
+	for _, product := range products {
+		if _, ok := r.products[product.GetID()]; ok || seen[product.GetID()] {
+			return fmt.Errorf("%w: product with id %s already exists", entity.ErrConflict, product.GetID())
+		}
+		seen[product.GetID()] = true
+	}
+	for _, product := range products {
+		r.store(product)
+	}
+	return nil
+}
+
+func (r *InMemoryProductRepository) List(ctx context.Context, filter domain.ProductFilter, page, limit int, sort domain.ProductSort) ([]*entity.Product, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	products := make([]*entity.Product, 0, len(r.products))
+	for _, p := range r.products {
+		if !matchesFilter(&p, filter) {
+			continue
+		}
+		product := p
+		products = append(products, &product)
+	}
+	sortProducts(products, sort)
+
+	totalCount := len(products)
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= totalCount {
+		return nil, totalCount, nil
+	}
+	end := offset + limit
+	if end > totalCount {
+		end = totalCount
+	}
+	return products[offset:end], totalCount, nil
+}
+
+func (r *InMemoryProductRepository) ListByCursor(ctx context.Context, filter domain.ProductFilter, sort domain.ProductSort, cursor *domain.ProductCursor, limit int) ([]*entity.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	products := make([]*entity.Product, 0, len(r.products))
+	for _, p := range r.products {
+		if !matchesFilter(&p, filter) {
+			continue
+		}
+		product := p
+		products = append(products, &product)
+	}
+	r.mu.RUnlock()
+
+	if cursor != nil && len(cursor.Values) != len(cursor.Sort.WithTiebreaker()) {
+		return nil, fmt.Errorf("%w: cursor does not match sort %q", entity.ErrInvalidInput, cursor.Sort)
+	}
+	sortProducts(products, sort)
+
+	start, end := 0, len(products)
+	if cursor != nil {
+		// Products sorting strictly after the cursor start at the first index
+		// comparing greater than it; those before end at the first index not less.
+		if cursor.Backward {
+			end = sortIndex(products, func(product *entity.Product) bool {
+				return compareToCursor(product, cursor) >= 0
+			})
+			if end-limit > start {
+				start = end - limit
+			}
+		} else {
+			start = sortIndex(products, func(product *entity.Product) bool {
+				return compareToCursor(product, cursor) > 0
+			})
+		}
+	}
+	if end-start > limit {
+		end = start + limit
+	}
+	return products[start:end], nil
+}
+
+// ForEach iterates over a snapshot of the stored products, so fn may safely call back into the repository.
+func (r *InMemoryProductRepository) ForEach(ctx context.Context, filter domain.ProductFilter, fn func(*entity.Product) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	products := make([]*entity.Product, 0, len(r.products))
+	for _, p := range r.products {
+		if !matchesFilter(&p, filter) {
+			continue
+		}
+		product := p
+		products = append(products, &product)
+	}
+	r.mu.RUnlock()
+
+	sortProducts(products, domain.ProductSort{{Field: domain.SortByID}})
+	for _, product := range products {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(product); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryProductRepository) Update(ctx context.Context, product *entity.Product) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.products[product.GetID()]
+	if !ok || stored.IsDeleted() {
+		return entity.NewProductNotFoundError(product.GetID())
+	}
+	if stored.GetVersion() != product.GetVersion() {
+		return entity.NewProductVersionConflictError(product.GetID(), product.GetVersion())
+	}
+	product.SetVersion(product.GetVersion() + 1)
+	r.store(product)
+	return nil
+}
+
+func (r *InMemoryProductRepository) GetByID(ctx context.Context, id string) (*entity.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	product, ok := r.products[id]
+	if !ok || product.IsDeleted() {
+		return nil, entity.NewProductNotFoundError(id)
+	}
+	return &product, nil
+}
+
+func (r *InMemoryProductRepository) GetByIDs(ctx context.Context, ids []string) ([]*entity.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var products []*entity.Product
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		product, ok := r.products[id]
+		if !ok || product.IsDeleted() || seen[id] {
+			continue
+		}
+		seen[id] = true
+		products = append(products, &product)
+	}
+	return products, nil
+}
+
+func (r *InMemoryProductRepository) Delete(ctx context.Context, id string) (*entity.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.products[id]
+	if !ok || product.IsDeleted() {
+		return nil, entity.NewProductNotFoundError(id)
+	}
+	product.SetDeletedAt(time.Now().UTC().Truncate(time.Microsecond))
+	product.SetVersion(product.GetVersion() + 1)
+	r.products[id] = product
+	return &product, nil
+}
+
+func (r *InMemoryProductRepository) Restore(ctx context.Context, id string) (*entity.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.products[id]
+	if !ok {
+		return nil, entity.NewProductNotFoundError(id)
+	}
+	if !product.IsDeleted() {
+		return nil, fmt.Errorf("%w: product with id %s is not deleted", entity.ErrConflict, id)
+	}
+	product.SetDeletedAt(time.Time{})
+	product.SetVersion(product.GetVersion() + 1)
+	r.products[id] = product
+	return &product, nil
+}
+
+func (r *InMemoryProductRepository) ListDeleted(ctx context.Context, page, limit int) ([]*entity.Product, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	products := make([]*entity.Product, 0)
+	for _, p := range r.products {
+		if !p.IsDeleted() {
+			continue
+		}
+		product := p
+		products = append(products, &product)
+	}
+	// Most recently deleted first, as ordered by ProductRepository
+	slices.SortFunc(products, func(a, b *entity.Product) int {
+		if c := b.GetDeletedAt().Compare(a.GetDeletedAt()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.GetID(), b.GetID())
+	})
+
+	totalCount := len(products)
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= totalCount {
+		return nil, totalCount, nil
+	}
+	end := offset + limit
+	if end > totalCount {
+		end = totalCount
+	}
+	return products[offset:end], totalCount, nil
+}
+
+func (r *InMemoryProductRepository) DeletePermanently(ctx context.Context, id string) (*entity.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.products[id]
+	if !ok {
+		return nil, entity.NewProductNotFoundError(id)
+	}
+	delete(r.products, id)
+	return &product, nil
+}
+
+func (r *InMemoryProductRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) ([]*entity.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged []*entity.Product
+	for id, p := range r.products {
+		if p.IsDeleted() && p.GetDeletedAt().Before(deletedBefore) {
+			delete(r.products, id)
+			product := p
+			purged = append(purged, &product)
+		}
+	}
+	return purged, nil
+}
+
+// sortProducts orders products by sort, mirroring the ORDER BY built by ProductRepository.
+// Ties are broken by id so paging is stable.
+func sortProducts(products []*entity.Product, sort domain.ProductSort) {
+	keys := sort.WithTiebreaker()
+	slices.SortStableFunc(products, func(a, b *entity.Product) int {
+		return compareToCursor(a, &domain.ProductCursor{Sort: sort, Values: sortValues(b, keys)})
+	})
+}
+
+// matchesFilter applies filter the same way productFilterClause does in SQL, leaving out the
+// products in the trash.
+func matchesFilter(product *entity.Product, filter domain.ProductFilter) bool {
+	if product.IsDeleted() {
+		return false
+	}
+	if filter.Status != "" && product.GetStatus() != filter.Status {
+		return false
+	}
+	if filter.MinPrice != nil && product.GetPrice() < *filter.MinPrice {
+		return false
+	}
+	if filter.MaxPrice != nil && product.GetPrice() > *filter.MaxPrice {
+		return false
+	}
+	if filter.NamePrefix != "" && !strings.HasPrefix(strings.ToLower(product.GetName()), strings.ToLower(filter.NamePrefix)) {
+		return false
+	}
+	if terms := tokenize(filter.Query); len(terms) > 0 {
+		document := make(map[string]bool)
+		for _, token := range tokenize(product.GetName() + " " + product.GetDescription()) {
+			document[token] = true
+		}
+		for _, term := range terms {
+			if !document[term] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// tokenize lowercases s and splits it into words made of letters and digits,
+// a rough equivalent of the 'simple' Postgres text search configuration.
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// sortIndex returns the index of the first product for which after is true,
+// or len(products) when there is none. products must be sorted.
+func sortIndex(products []*entity.Product, after func(*entity.Product) bool) int {
+	return sort.Search(len(products), func(i int) bool {
+		return after(products[i])
+	})
+}
+
+// compareToCursor orders product relative to the cursor position using the same
+// rules as sortProducts, returning -1, 0 or 1.
+func compareToCursor(product *entity.Product, cursor *domain.ProductCursor) int {
+	for i, key := range cursor.Sort.WithTiebreaker() {
+		c := compareSortValue(key.Field, domain.ProductSortValue(product, key.Field), cursor.Values[i])
+		if key.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// sortValues returns the values of product for each of keys.
+func sortValues(product *entity.Product, keys domain.ProductSort) []string {
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = domain.ProductSortValue(product, key.Field)
+	}
+	return values
+}
+
+// compareSortValue compares two values formatted by domain.ProductSortValue, numerically
+// for prices and chronologically for creation times.
+func compareSortValue(field, a, b string) int {
+	switch field {
+	case domain.SortByPrice:
+		x, _ := strconv.ParseFloat(a, 64)
+		y, _ := strconv.ParseFloat(b, 64)
+		return cmp.Compare(x, y)
+	case domain.SortByCreatedAt:
+		x, _ := time.Parse(time.RFC3339Nano, a)
+		y, _ := time.Parse(time.RFC3339Nano, b)
+		return x.Compare(y)
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// store keeps a copy of product. The events raised by product stay with the caller, which
+// publishes them, so that they are not raised again by the products read back.
+func (r *InMemoryProductRepository) store(product *entity.Product) {
+	stored := *product
+	stored.ClearEvents()
+	r.products[product.GetID()] = stored
+}
@@ -0,0 +1,380 @@
+package database_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type InMemoryProductRepositoryTestSuite struct {
+	suite.Suite
+	Repository *database.InMemoryProductRepository
+}
+
+func (suite *InMemoryProductRepositoryTestSuite) SetupTest() {
+	suite.Repository = database.NewInMemoryProductRepository()
+}
+
+func (suite *InMemoryProductRepositoryTestSuite) TestCreateAndGetByID() {
+	product, err := entity.NewProduct("Test Product", "Test Description", 10.0)
+	suite.Require().NoError(err)
+
+	err = suite.Repository.Create(context.Background(), product)
+	suite.Require().NoError(err)
+
+	retrievedProduct, err := suite.Repository.GetByID(context.Background(), product.GetID())
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), product.GetName(), retrievedProduct.GetName())
+	assert.Equal(suite.T(), product.GetDescription(), retrievedProduct.GetDescription())
+	assert.Equal(suite.T(), product.GetPrice(), retrievedProduct.GetPrice())
+	assert.Equal(suite.T(), product.GetStatus(), retrievedProduct.GetStatus())
+
+	// Mutating the returned product must not change the stored one
+	err = retrievedProduct.ChangePrice(99.0)
+	suite.Require().NoError(err)
+	storedProduct, err := suite.Repository.GetByID(context.Background(), product.GetID())
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 10.0, storedProduct.GetPrice())
+
+	err = suite.Repository.Create(context.Background(), product)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *InMemoryProductRepositoryTestSuite) TestGetByIDNotFound() {
+	product, err := suite.Repository.GetByID(context.Background(), "non-existent-id")
+	assert.EqualError(suite.T(), err, "product with id non-existent-id not found")
+	assert.Nil(suite.T(), product)
+}
+
+func (suite *InMemoryProductRepositoryTestSuite) TestGetByIDs() {
+	var ids []string
+	for _, name := range []string{"Product A", "Product B", "Product C"} {
+		product, err := entity.NewProduct(name, "description", 10.0)
+		suite.Require().NoError(err)
+		suite.Require().NoError(suite.Repository.Create(context.Background(), product))
+		ids = append(ids, product.GetID())
+	}
+	_, err := suite.Repository.Delete(context.Background(), ids[2])
+	suite.Require().NoError(err)
+
+	products, err := suite.Repository.GetByIDs(context.Background(), []string{ids[1], "non-existent-id", ids[0], ids[2]})
+	suite.Require().NoError(err)
+	var found []string
+	for _, product := range products {
+		found = append(found, product.GetID())
+	}
+	assert.ElementsMatch(suite.T(), []string{ids[0], ids[1]}, found)
+
+	products, err = suite.Repository.GetByIDs(context.Background(), []string{"non-existent-id"})
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), products)
+}
+
+func (suite *InMemoryProductRepositoryTestSuite) TestList() {
+	products := []struct {
+		name        string
+		description string
+		price       float64
+	}{
+		{"Product E", "Description E", 50.0},
+		{"Product B", "Description B", 20.0},
+		{"Product D", "Description D", 40.0},
+		{"Product A", "Description A", 10.0},
+		{"Product C", "Description C", 30.0},
+	}
+
+	for _, p := range products {
+		product, err := entity.NewProduct(p.name, p.description, p.price)
+		suite.Require().NoError(err)
+		err = suite.Repository.Create(context.Background(), product)
+		suite.Require().NoError(err)
+	}
+
+	testCases := []struct {
+		name          string
+		page          int
+		limit         int
+		sort          string
+		expectedCount int
+		expectedTotal int
+	}{
+		{"First page, default sort", 1, 3, "id", 3, 5},
+		{"Second page, default sort", 2, 3, "id", 2, 5},
+		{"Page out of range", 3, 3, "id", 0, 5},
+		{"All products, sort by price", 1, 10, "price", 5, 5},
+		{"All products, sort by name", 1, 10, "name", 5, 5},
+		{"All products, sort by price descending", 1, 10, "-price", 5, 5},
+	}
+
+	for _, tc := range testCases {
+		suite.T().Run(tc.name, func(t *testing.T) {
+			sort, err := domain.ParseProductSort(tc.sort)
+			suite.Require().NoError(err)
+			resultProducts, totalCount, err := suite.Repository.List(context.Background(), domain.ProductFilter{}, tc.page, tc.limit, sort)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCount, len(resultProducts))
+			assert.Equal(t, tc.expectedTotal, totalCount)
+
+			for i := 1; i < len(resultProducts); i++ {
+				switch tc.sort {
+				case "price":
+					assert.GreaterOrEqual(t, resultProducts[i].GetPrice(), resultProducts[i-1].GetPrice())
+				case "-price":
+					assert.LessOrEqual(t, resultProducts[i].GetPrice(), resultProducts[i-1].GetPrice())
+				case "name":
+					assert.GreaterOrEqual(t, resultProducts[i].GetName(), resultProducts[i-1].GetName())
+				default:
+					assert.Greater(t, resultProducts[i].GetID(), resultProducts[i-1].GetID())
+				}
+			}
+		})
+	}
+}
+
+func (suite *InMemoryProductRepositoryTestSuite) TestListMultiFieldSort() {
+	products := []struct {
+		name    string
+		price   float64
+		enabled bool
+	}{
+		{"Product B", 20.0, true},
+		{"Product A", 20.0, false},
+		{"Product C", 10.0, true},
+		{"Product D", 30.0, false},
+		{"Product E", 10.0, false},
+	}
+
+	for _, p := range products {
+		product, err := entity.NewProduct(p.name, "Description", p.price)
+		suite.Require().NoError(err)
+		if p.enabled {
+			suite.Require().NoError(product.Enable())
+		}
+		suite.Require().NoError(suite.Repository.Create(context.Background(), product))
+	}
+
+	testCases := []struct {
+		sort          string
+		expectedNames []string
+	}{
+		{"-price,name", []string{"Product D", "Product A", "Product B", "Product C", "Product E"}},
+		{"price,-name", []string{"Product E", "Product C", "Product B", "Product A", "Product D"}},
+		{"status,-price", []string{"Product D", "Product A", "Product E", "Product B", "Product C"}},
+	}
+
+	for _, tc := range testCases {
+		suite.T().Run(tc.sort, func(t *testing.T) {
+			sort, err := domain.ParseProductSort(tc.sort)
+			suite.Require().NoError(err)
+			resultProducts, _, err := suite.Repository.List(context.Background(), domain.ProductFilter{}, 1, 10, sort)
+			assert.NoError(t, err)
+
+			var names []string
+			for _, product := range resultProducts {
+				names = append(names, product.GetName())
+			}
+			assert.Equal(t, tc.expectedNames, names)
+		})
+	}
+}
+
+func (suite *InMemoryProductRepositoryTestSuite) TestListWithFilters() {
+	products := []struct {
+		name        string
+		description string
+		price       float64
+		enabled     bool
+	}{
+		{"Cadeira Gamer", "Cadeira ergonômica com apoio lombar", 999.99, true},
+		{"Cadeira de Escritório", "Cadeira com rodinhas", 499.90, false},
+		{"Teclado Mecânico", "Teclado RGB com switches azuis", 449.99, true},
+		{"Mouse Gamer", "Mouse com 7 botões programáveis", 299.99, false},
+		{"100% Algodão_Camiseta", "Camiseta básica", 49.90, true},
+	}
+
+	for _, p := range products {
+		product, err := entity.NewProduct(p.name, p.description, p.price)
+		suite.Require().NoError(err)
+		if p.enabled {
+			suite.Require().NoError(product.Enable())
+		}
+		suite.Require().NoError(suite.Repository.Create(context.Background(), product))
+	}
+
+	minPrice, maxPrice := 300.0, 1000.0
+
+	testCases := []struct {
+		name          string
+		filter        domain.ProductFilter
+		expectedNames []string
+	}{
+		{"By status", domain.ProductFilter{Status: entity.DISABLED}, []string{"Cadeira de Escritório", "Mouse Gamer"}},
+		{"By price range", domain.ProductFilter{MinPrice: &minPrice, MaxPrice: &maxPrice}, []string{"Cadeira Gamer", "Cadeira de Escritório", "Teclado Mecânico"}},
+		{"By name prefix", domain.ProductFilter{NamePrefix: "cadeira"}, []string{"Cadeira Gamer", "Cadeira de Escritório"}},
+		{"By name prefix with wildcards", domain.ProductFilter{NamePrefix: "100% Algodão_"}, []string{"100% Algodão_Camiseta"}},
+		{"Full-text search", domain.ProductFilter{Query: "gamer"}, []string{"Cadeira Gamer", "Mouse Gamer"}},
+		{"Full-text search over description", domain.ProductFilter{Query: "RGB switches"}, []string{"Teclado Mecânico"}},
+		{"Combined filters", domain.ProductFilter{Status: entity.ENABLED, Query: "cadeira"}, []string{"Cadeira Gamer"}},
+		{"No matches", domain.ProductFilter{Query: "monitor"}, nil},
+	}
+
+	for _, tc := range testCases {
+		suite.T().Run(tc.name, func(t *testing.T) {
+			resultProducts, totalCount, err := suite.Repository.List(context.Background(), tc.filter, 1, 2, domain.ProductSort{{Field: domain.SortByPrice}})
+			assert.NoError(t, err)
+			assert.Equal(t, len(tc.expectedNames), totalCount)
+
+			var names []string
+			err = suite.Repository.ForEach(context.Background(), tc.filter, func(product *entity.Product) error {
+				names = append(names, product.GetName())
+				return nil
+			})
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedNames, names)
+			assert.LessOrEqual(t, len(resultProducts), 2)
+		})
+	}
+}
+
+func (suite *InMemoryProductRepositoryTestSuite) TestUpdate() {
+	product, err := entity.NewProduct("Test Product", "Test Description", 10.0)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.Repository.Create(context.Background(), product))
+
+	suite.Require().NoError(product.Update("Updated Product", "Updated Description"))
+	suite.Require().NoError(product.ChangePrice(20.0))
+	suite.Require().NoError(suite.Repository.Update(context.Background(), product))
+
+	updatedProduct, err := suite.Repository.GetByID(context.Background(), product.GetID())
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "Updated Product", updatedProduct.GetName())
+	assert.Equal(suite.T(), "Updated Description", updatedProduct.GetDescription())
+	assert.Equal(suite.T(), 20.0, updatedProduct.GetPrice())
+	assert.Equal(suite.T(), 2, updatedProduct.GetVersion())
+	assert.Equal(suite.T(), 2, product.GetVersion())
+
+	// Writing a copy read before the last update would overwrite it
+	stale := *updatedProduct
+	stale.SetVersion(1)
+	err = suite.Repository.Update(context.Background(), &stale)
+	suite.Require().ErrorIs(err, entity.ErrConflict)
+	var conflictErr *entity.ProductVersionConflictError
+	suite.Require().ErrorAs(err, &conflictErr)
+
+	missing, err := entity.NewProduct("Missing Product", "Missing Description", 10.0)
+	suite.Require().NoError(err)
+	err = suite.Repository.Update(context.Background(), missing)
+	assert.EqualError(suite.T(), err, "product with id "+missing.GetID()+" not found")
+}
+
+func (suite *InMemoryProductRepositoryTestSuite) TestDelete() {
+	product, err := entity.NewProduct("Test Product", "Test Description", 10.0)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.Repository.Create(context.Background(), product))
+
+	deleted, err := suite.Repository.Delete(context.Background(), product.GetID())
+	suite.Require().NoError(err)
+	assert.True(suite.T(), deleted.IsDeleted())
+	assert.Equal(suite.T(), 2, deleted.GetVersion())
+
+	_, err = suite.Repository.GetByID(context.Background(), product.GetID())
+	assert.Error(suite.T(), err)
+
+	_, err = suite.Repository.Delete(context.Background(), product.GetID())
+	assert.EqualError(suite.T(), err, "product with id "+product.GetID()+" not found")
+
+	// Deleted products are hidden from the listings and cannot be updated
+	_, totalCount, err := suite.Repository.List(context.Background(), domain.ProductFilter{}, 1, 10, domain.ProductSort{{Field: domain.SortByID}})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, totalCount)
+	err = suite.Repository.Update(context.Background(), product)
+	assert.ErrorIs(suite.T(), err, entity.ErrNotFound)
+}
+
+func (suite *InMemoryProductRepositoryTestSuite) TestTrash() {
+	ctx := context.Background()
+	first, err := entity.NewProduct("First Product", "Description", 10.0)
+	suite.Require().NoError(err)
+	second, err := entity.NewProduct("Second Product", "Description", 20.0)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.Repository.CreateBatch(ctx, []*entity.Product{first, second}))
+
+	_, err = suite.Repository.Delete(ctx, first.GetID())
+	suite.Require().NoError(err)
+	time.Sleep(time.Millisecond)
+	_, err = suite.Repository.Delete(ctx, second.GetID())
+	suite.Require().NoError(err)
+
+	deleted, totalCount, err := suite.Repository.ListDeleted(ctx, 1, 10)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 2, totalCount)
+	suite.Require().Len(deleted, 2)
+	assert.Equal(suite.T(), second.GetID(), deleted[0].GetID(), "most recently deleted first")
+	assert.True(suite.T(), deleted[1].IsDeleted())
+	assert.Equal(suite.T(), 2, deleted[1].GetVersion())
+
+	restored, err := suite.Repository.Restore(ctx, first.GetID())
+	suite.Require().NoError(err)
+	assert.False(suite.T(), restored.IsDeleted())
+	assert.Equal(suite.T(), 3, restored.GetVersion())
+	_, err = suite.Repository.GetByID(ctx, first.GetID())
+	suite.Require().NoError(err)
+
+	_, err = suite.Repository.Restore(ctx, first.GetID())
+	assert.ErrorIs(suite.T(), err, entity.ErrConflict)
+	_, err = suite.Repository.Restore(ctx, "non-existent-id")
+	assert.ErrorIs(suite.T(), err, entity.ErrNotFound)
+
+	// Only the products deleted before the given time are purged
+	purged, err := suite.Repository.PurgeDeleted(ctx, time.Now().Add(-time.Hour))
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), purged)
+	purged, err = suite.Repository.PurgeDeleted(ctx, time.Now().Add(time.Second))
+	suite.Require().NoError(err)
+	suite.Require().Len(purged, 1)
+	assert.Equal(suite.T(), second.GetID(), purged[0].GetID())
+	_, err = suite.Repository.Restore(ctx, second.GetID())
+	assert.ErrorIs(suite.T(), err, entity.ErrNotFound)
+
+	removed, err := suite.Repository.DeletePermanently(ctx, first.GetID())
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "First Product", removed.GetName())
+	_, totalCount, err = suite.Repository.ListDeleted(ctx, 1, 10)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, totalCount)
+	_, err = suite.Repository.DeletePermanently(ctx, first.GetID())
+	assert.ErrorIs(suite.T(), err, entity.ErrNotFound)
+}
+
+func (suite *InMemoryProductRepositoryTestSuite) TestConcurrentAccess() {
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			product, err := entity.NewProduct("Concurrent Product", "Description", 10.0)
+			if err != nil {
+				return
+			}
+			_ = suite.Repository.Create(context.Background(), product)
+			_, _, _ = suite.Repository.List(context.Background(), domain.ProductFilter{}, 1, 10, domain.ProductSort{{Field: domain.SortByName}})
+		}()
+	}
+	wg.Wait()
+
+	_, totalCount, err := suite.Repository.List(context.Background(), domain.ProductFilter{}, 1, 10, domain.ProductSort{{Field: domain.SortByID}})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 50, totalCount)
+}
+
+func TestInMemoryProductRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(InMemoryProductRepositoryTestSuite))
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+)
+
+// outboxMessage is an event stored by InMemoryProductOutboxRepository.
+type outboxMessage struct {
+	event     entity.ProductEvent
+	published bool
+	dead      bool
+	attempts  int
+	lastError string
+	// claimedUntil is the end of the claim set by ClaimPending, zero when the event is not claimed.
+	claimedUntil time.Time
+}
+
+// InMemoryProductOutboxRepository is a thread-safe, non-persistent implementation of
+// OutboxRepositoryInterface, the companion of InMemoryProductRepository.
+type InMemoryProductOutboxRepository struct {
+	mu       sync.Mutex
+	messages []*outboxMessage
+}
+
+func NewInMemoryProductOutboxRepository() *InMemoryProductOutboxRepository {
+	return &InMemoryProductOutboxRepository{}
+}
+
+func (r *InMemoryProductOutboxRepository) Append(ctx context.Context, events ...*entity.ProductEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, event := range events {
+		stored := *event
+		stored.Data = maps.Clone(event.Data)
+		r.messages = append(r.messages, &outboxMessage{event: stored})
+	}
+	return nil
+}
+
+func (r *InMemoryProductOutboxRepository) ClaimPending(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.ProductEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var events []*entity.ProductEvent
+	for _, message := range r.messages {
+		if len(events) == limit {
+			break
+		}
+		if message.published || message.dead || message.claimedUntil.After(now) {
+			continue
+		}
+		message.claimedUntil = now.Add(lease)
+		event := message.event
+		event.Data = maps.Clone(message.event.Data)
+		events = append(events, &event)
+	}
+	return events, nil
+}
+
+func (r *InMemoryProductOutboxRepository) MarkPublished(ctx context.Context, ids ...string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	published := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		published[id] = true
+	}
+	for _, message := range r.messages {
+		if published[message.event.ID] {
+			message.published = true
+			message.claimedUntil = time.Time{}
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryProductOutboxRepository) MarkFailed(ctx context.Context, id string, reason string, maxAttempts int) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, message := range r.messages {
+		if message.event.ID == id {
+			message.attempts++
+			message.lastError = reason
+			message.claimedUntil = time.Time{}
+			message.dead = message.attempts >= maxAttempts
+			return message.dead, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *InMemoryProductOutboxRepository) Release(ctx context.Context, ids ...string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	released := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		released[id] = true
+	}
+	for _, message := range r.messages {
+		if released[message.event.ID] {
+			message.claimedUntil = time.Time{}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryProductOutboxRepository(t *testing.T) {
+	repository := database.NewInMemoryProductOutboxRepository()
+	ctx := context.Background()
+
+	product, err := entity.NewProduct("Product", "description", 10)
+	require.NoError(t, err)
+	require.NoError(t, product.ChangePrice(12))
+	require.NoError(t, product.Enable())
+	events := product.PullEvents()
+	require.NoError(t, repository.Append(ctx, events...))
+
+	now := time.Now()
+	claimed, err := repository.ClaimPending(ctx, now, time.Minute, 2)
+	require.NoError(t, err)
+	require.Equal(t, events[:2], claimed, "oldest first")
+
+	pending, err := repository.ClaimPending(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Equal(t, events[2:], pending, "claimed events are skipped")
+	pending, err = repository.ClaimPending(ctx, now.Add(time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	require.Equal(t, events, pending, "until their claim expires")
+
+	dead, err := repository.MarkFailed(ctx, events[0].ID, "broker unavailable", 2)
+	require.NoError(t, err)
+	require.False(t, dead)
+	require.NoError(t, repository.Release(ctx, events[1].ID))
+	pending, err = repository.ClaimPending(ctx, now.Add(time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	require.Equal(t, events[:2], pending, "failed and released events can be claimed again")
+
+	require.NoError(t, repository.MarkPublished(ctx, events[0].ID, events[1].ID))
+	pending, err = repository.ClaimPending(ctx, now.Add(2*time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	require.Equal(t, events[2:], pending)
+
+	// Events cannot be changed through the values given to Append or returned by ClaimPending
+	events[2].Data["status"] = "changed"
+	pending[0].Data["status"] = "changed"
+	pending, err = repository.ClaimPending(ctx, now.Add(3*time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	require.Equal(t, entity.ENABLED, pending[0].Data["status"])
+
+	dead, err = repository.MarkFailed(ctx, events[2].ID, "broker unavailable", 1)
+	require.NoError(t, err)
+	require.True(t, dead)
+	pending, err = repository.ClaimPending(ctx, now.Add(4*time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	require.Empty(t, pending, "dead events are no longer pending")
+}
+
+func TestInMemoryProductRepositoryDoesNotKeepEvents(t *testing.T) {
+	repository := database.NewInMemoryProductRepository()
+	product, err := entity.NewProduct("Product", "description", 10)
+	require.NoError(t, err)
+	require.NoError(t, repository.Create(context.Background(), product))
+
+	stored, err := repository.GetByID(context.Background(), product.GetID())
+	require.NoError(t, err)
+	require.Empty(t, stored.PullEvents())
+	require.Len(t, product.PullEvents(), 1)
+}
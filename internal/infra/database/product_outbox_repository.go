@@ -0,0 +1,134 @@
+package database
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/lib/pq"
+)
+
+type ProductOutboxRepository struct {
+	Db *sql.DB
+}
+
+func NewProductOutboxRepository(db *sql.DB) *ProductOutboxRepository {
+	return &ProductOutboxRepository{Db: db}
+}
+
+// Append inserts events with multi-row INSERT statements of at most batchSize rows each, inside
+// the transaction in ctx if any.
+func (r *ProductOutboxRepository) Append(ctx context.Context, events ...*entity.ProductEvent) error {
+	for start := 0; start < len(events); start += batchSize {
+		end := start + batchSize
+		if end > len(events) {
+			end = len(events)
+		}
+		batch := events[start:end]
+
+		var query strings.Builder
+		query.WriteString("INSERT INTO product_outbox (id, event_type, product_id, version, data, occurred_at) VALUES ")
+		args := make([]any, 0, len(batch)*6)
+		for i, event := range batch {
+			data, err := json.Marshal(event.Data)
+			if err != nil {
+				return err
+			}
+
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			n := i * 6
+			fmt.Fprintf(&query, "($%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6)
+			args = append(args, event.ID, event.Type, event.ProductID, event.Version, data, event.OccurredAt)
+		}
+
+		_, err := conn(ctx, r.Db).ExecContext(ctx, query.String(), args...)
+		if err != nil {
+			return translateError("", err)
+		}
+	}
+	return nil
+}
+
+// ClaimPending sets the claim of the events it returns in a single statement, skipping the rows
+// locked by concurrent callers, so that several instances of the service can relay the outbox
+// without publishing the same event twice.
+func (r *ProductOutboxRepository) ClaimPending(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.ProductEvent, error) {
+	query := "UPDATE product_outbox SET claimed_until = $2 WHERE id IN (" +
+		"SELECT id FROM product_outbox WHERE published_at IS NULL AND dead_at IS NULL " +
+		"AND (claimed_until IS NULL OR claimed_until <= $1) ORDER BY seq LIMIT $3 FOR UPDATE SKIP LOCKED) " +
+		"RETURNING id, event_type, product_id, version, data, occurred_at, seq"
+	rows, err := conn(ctx, r.Db).QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// RETURNING does not keep the order of the subquery
+	type claimed struct {
+		event *entity.ProductEvent
+		seq   int64
+	}
+	var all []claimed
+	for rows.Next() {
+		var event entity.ProductEvent
+		var data []byte
+		var seq int64
+		err = rows.Scan(&event.ID, &event.Type, &event.ProductID, &event.Version, &data, &event.OccurredAt, &seq)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(data, &event.Data); err != nil {
+			return nil, err
+		}
+		event.OccurredAt = event.OccurredAt.UTC()
+		all = append(all, claimed{event: &event, seq: seq})
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	events := make([]*entity.ProductEvent, len(all))
+	slices.SortFunc(all, func(a, b claimed) int { return cmp.Compare(a.seq, b.seq) })
+	for i, c := range all {
+		events[i] = c.event
+	}
+	return events, nil
+}
+
+func (r *ProductOutboxRepository) MarkPublished(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := conn(ctx, r.Db).ExecContext(ctx,
+		"UPDATE product_outbox SET published_at = now(), claimed_until = NULL WHERE id = ANY($1)", pq.Array(ids))
+	return err
+}
+
+func (r *ProductOutboxRepository) MarkFailed(ctx context.Context, id string, reason string, maxAttempts int) (bool, error) {
+	query := "UPDATE product_outbox SET attempts = attempts + 1, last_error = $2, claimed_until = NULL, " +
+		"dead_at = CASE WHEN attempts + 1 >= $3 THEN now() END WHERE id = $1 RETURNING dead_at IS NOT NULL"
+	var dead bool
+	err := conn(ctx, r.Db).QueryRowContext(ctx, query, id, reason, maxAttempts).Scan(&dead)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return dead, err
+}
+
+func (r *ProductOutboxRepository) Release(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := conn(ctx, r.Db).ExecContext(ctx,
+		"UPDATE product_outbox SET claimed_until = NULL WHERE id = ANY($1)", pq.Array(ids))
+	return err
+}
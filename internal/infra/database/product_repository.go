@@ -1,74 +1,135 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	domain "github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// productColumns lists the columns read by scanProduct, in order.
+const productColumns = "id, name, description, price, status, created_at, version, deleted_at"
+
+// sortColumns maps the sort fields accepted by domain.ParseProductSort to columns.
+var sortColumns = map[string]string{
+	domain.SortByID:        "id",
+	domain.SortByName:      "name",
+	domain.SortByPrice:     "price",
+	domain.SortByStatus:    "status",
+	domain.SortByCreatedAt: "created_at",
+}
+
+// batchSize is the maximum number of rows written by a single INSERT in CreateBatch.
+const batchSize = 500
+
+// Postgres error codes translated into domain errors.
+const (
+	pqUniqueViolation           = "23505"
+	pqInvalidTextRepresentation = "22P02"
+	pqUndefinedTable            = "42P01"
 )
 
 type ProductRepository struct {
-	Db *sql.DB
+	Db     *sql.DB
+	Logger *slog.Logger
 }
 
-func NewProductRepository(db *sql.DB) *ProductRepository {
-	fmt.Printf("Creating ProductRepository with db: %v\n", db)
-	return &ProductRepository{Db: db}
+func NewProductRepository(db *sql.DB, logger *slog.Logger) *ProductRepository {
+	return &ProductRepository{Db: db, Logger: logger}
 }
 
-func (r *ProductRepository) Create(product *domain.Product) error {
-	stmt, err := r.Db.Prepare("INSERT INTO products (id, name, description, price, status) VALUES ($1, $2, $3, $4, $5)")
+func (r *ProductRepository) Create(ctx context.Context, product *entity.Product) error {
+	stmt, err := conn(ctx, r.Db).PrepareContext(ctx, "INSERT INTO products (id, name, description, price, status, created_at, version) VALUES ($1, $2, $3, $4, $5, $6, $7)")
 	if err != nil {
 		return err
 	}
-	_, err = stmt.Exec(product.GetID(), product.GetName(), product.GetDescription(), product.GetPrice(), product.GetStatus())
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, product.GetID(), product.GetName(), product.GetDescription(), product.GetPrice(), product.GetStatus(),
+		product.GetCreatedAt(), product.GetVersion())
 	if err != nil {
-		return err
+		return translateError(product.GetID(), err)
+	}
+	return nil
+}
+
+// CreateBatch inserts products inside a single transaction, joining the one in ctx if any,
+// using multi-row INSERT statements of at most batchSize rows each. Either every product is
+// stored or none is.
+func (r *ProductRepository) CreateBatch(ctx context.Context, products []*entity.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	return NewTransactionManager(r.Db).WithinTransaction(ctx, func(ctx context.Context) error {
+		return r.createBatch(ctx, products)
+	})
+}
+
+func (r *ProductRepository) createBatch(ctx context.Context, products []*entity.Product) error {
+	for start := 0; start < len(products); start += batchSize {
+		end := start + batchSize
+		if end > len(products) {
+			end = len(products)
+		}
+		batch := products[start:end]
+
+		var query strings.Builder
+		query.WriteString("INSERT INTO products (id, name, description, price, status, created_at, version) VALUES ")
+		args := make([]any, 0, len(batch)*7)
+		for i, product := range batch {
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			n := i * 7
+			fmt.Fprintf(&query, "($%d, $%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7)
+			args = append(args, product.GetID(), product.GetName(), product.GetDescription(), product.GetPrice(), product.GetStatus(),
+				product.GetCreatedAt(), product.GetVersion())
+		}
+
+		_, err := conn(ctx, r.Db).ExecContext(ctx, query.String(), args...)
+		if err != nil {
+			return translateError("", err)
+		}
 	}
 	return nil
 }
 
-func (r *ProductRepository) List(page, limit int, sort string) ([]*domain.Product, int, error) {
+func (r *ProductRepository) List(ctx context.Context, filter domain.ProductFilter, page, limit int, sort domain.ProductSort) ([]*entity.Product, int, error) {
 	offset := (page - 1) * limit
+	where, args := productFilterClause(filter)
 
-	// Count total products
+	// Count total products matching the filter
 	var totalCount int
-	err := r.Db.QueryRow("SELECT COUNT(*) FROM products").Scan(&totalCount)
+	err := conn(ctx, r.Db).QueryRowContext(ctx, "SELECT COUNT(*) FROM products"+where, args...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	// Validate and sanitize sort field
-	validSortFields := map[string]bool{"id": true, "name": true, "price": true}
-	if !validSortFields[sort] {
-		sort = "id"
-	}
-
-	query := fmt.Sprintf("SELECT id, name, description, price, status FROM products ORDER BY %s LIMIT $1 OFFSET $2", sort)
+	query := fmt.Sprintf("SELECT %s FROM products%s ORDER BY %s LIMIT $%d OFFSET $%d",
+		productColumns, where, orderByClause(sort.WithTiebreaker(), false), len(args)+1, len(args)+2)
 
-	// Print the query with actual values
-	fmt.Printf("Executing main query: %s [LIMIT %d OFFSET %d]\n", query, limit, offset)
+	r.Logger.DebugContext(ctx, "listing products", "query", query, "limit", limit, "offset", offset)
 
-	rows, err := r.Db.Query(query, limit, offset)
+	rows, err := conn(ctx, r.Db).QueryContext(ctx, query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var products []*domain.Product
+	var products []*entity.Product
 	for rows.Next() {
-		var id, name, description, status string
-		var price float64
-
-		err := rows.Scan(&id, &name, &description, &price, &status)
-		if err != nil {
-			return nil, 0, err
-		}
-		product, err := domain.NewProduct(name, description, price)
+		product, err := scanProduct(rows)
 		if err != nil {
 			return nil, 0, err
 		}
-		product.SetID(id)
-
 		products = append(products, product)
 	}
 
@@ -78,72 +139,381 @@ func (r *ProductRepository) List(page, limit int, sort string) ([]*domain.Produc
 	return products, totalCount, nil
 }
 
-func (r *ProductRepository) Update(product *domain.Product) error {
-	stmt, err := r.Db.Prepare("UPDATE products SET name = $1, description = $2, price = $3 WHERE id = $4")
+// ListByCursor uses keyset pagination: instead of an OFFSET it selects the rows that sort
+// after (or before) the cursor position, so deep pages stay fast and concurrent inserts
+// never cause duplicated or skipped products.
+func (r *ProductRepository) ListByCursor(ctx context.Context, filter domain.ProductFilter, sort domain.ProductSort, cursor *domain.ProductCursor, limit int) ([]*entity.Product, error) {
+	where, args := productFilterClause(filter)
+	keys := sort.WithTiebreaker()
+
+	// Backward pages are read in reverse order and flipped afterwards
+	backward := cursor != nil && cursor.Backward
+	if cursor != nil {
+		if len(cursor.Values) != len(keys) {
+			return nil, fmt.Errorf("%w: cursor does not match sort %q", entity.ErrInvalidInput, sort)
+		}
+		var condition string
+		condition, args = keysetCondition(keys, cursor.Values, backward, args)
+		where += " AND " + condition
+	}
+
+	args = append(args, limit)
+	query := fmt.Sprintf("SELECT %s FROM products%s ORDER BY %s LIMIT $%d",
+		productColumns, where, orderByClause(keys, backward), len(args))
+
+	rows, err := conn(ctx, r.Db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []*entity.Product
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if backward {
+		for i, j := 0, len(products)-1; i < j; i, j = i+1, j-1 {
+			products[i], products[j] = products[j], products[i]
+		}
+	}
+	return products, nil
+}
+
+// orderByClause builds the ORDER BY list for keys, flipping every direction when reverse is set.
+func orderByClause(keys domain.ProductSort, reverse bool) string {
+	terms := make([]string, len(keys))
+	for i, key := range keys {
+		direction := "ASC"
+		if key.Desc != reverse {
+			direction = "DESC"
+		}
+		terms[i] = sortColumns[key.Field] + " " + direction
+	}
+	return strings.Join(terms, ", ")
+}
+
+// keysetCondition builds "(k1 > v1) OR (k1 = v1 AND k2 > v2) OR ..." selecting the rows after
+// the position given by values, or before it when backward is set, appending the values to
+// args. Unlike a row comparison, the expanded form supports keys sorted in different directions.
+func keysetCondition(keys domain.ProductSort, values []string, backward bool, args []any) (string, []any) {
+	positions := make([]int, len(values))
+	for i, value := range values {
+		args = append(args, value)
+		positions[i] = len(args)
+	}
+
+	alternatives := make([]string, len(keys))
+	for i, key := range keys {
+		terms := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			terms = append(terms, fmt.Sprintf("%s = $%d", sortColumns[keys[j].Field], positions[j]))
+		}
+		comparison := ">"
+		if key.Desc != backward {
+			comparison = "<"
+		}
+		terms = append(terms, fmt.Sprintf("%s %s $%d", sortColumns[key.Field], comparison, positions[i]))
+		alternatives[i] = "(" + strings.Join(terms, " AND ") + ")"
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+// ForEach streams products from a single query, scanning one row at a time.
+func (r *ProductRepository) ForEach(ctx context.Context, filter domain.ProductFilter, fn func(*entity.Product) error) error {
+	where, args := productFilterClause(filter)
+	query := "SELECT " + productColumns + " FROM products" + where + " ORDER BY id"
+
+	rows, err := conn(ctx, r.Db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return err
+		}
+		if err = fn(product); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Update writes product only if it is still at the version it was read with, then increments
+// the version of both the row and product. A product modified in the meantime is reported as
+// a ProductVersionConflictError.
+func (r *ProductRepository) Update(ctx context.Context, product *entity.Product) error {
+	stmt, err := conn(ctx, r.Db).PrepareContext(ctx, "UPDATE products SET name = $1, description = $2, price = $3, status = $4, version = version + 1 WHERE id = $5 AND version = $6 AND deleted_at IS NULL")
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(product.GetName(), product.GetDescription(), product.GetPrice(),
-		product.GetID())
+	result, err := stmt.ExecContext(ctx, product.GetName(), product.GetDescription(), product.GetPrice(),
+		product.GetStatus(), product.GetID(), product.GetVersion())
+	if err != nil {
+		return translateError(product.GetID(), err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+
+	if rowsAffected == 0 {
+		// Tell a missing product apart from one updated by someone else
+		var exists bool
+		err = conn(ctx, r.Db).QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM products WHERE id = $1 AND deleted_at IS NULL)", product.GetID()).Scan(&exists)
+		if err != nil {
+			return translateError(product.GetID(), err)
+		}
+		if exists {
+			return entity.NewProductVersionConflictError(product.GetID(), product.GetVersion())
+		}
+		return entity.NewProductNotFoundError(product.GetID())
+	}
+	product.SetVersion(product.GetVersion() + 1)
 	return nil
 }
 
-func (r *ProductRepository) GetByID(id string) (*domain.Product, error) {
-	query := "SELECT id, name, description, price, status FROM products WHERE id = $1"
+func (r *ProductRepository) GetByID(ctx context.Context, id string) (*entity.Product, error) {
+	query := "SELECT " + productColumns + " FROM products WHERE id = $1 AND deleted_at IS NULL"
 
-	row := r.Db.QueryRow(query, id)
+	product, err := scanProduct(conn(ctx, r.Db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.NewProductNotFoundError(id)
+		}
+		return nil, translateError(id, err)
+	}
 
-	var product *domain.Product
-	var idStr, name, description, status string
-	var price float64
+	return product, nil
+}
+
+func (r *ProductRepository) GetByIDs(ctx context.Context, ids []string) ([]*entity.Product, error) {
+	// Postgres rejects the whole query when one of the ids is not a UUID, and such ids
+	// cannot match a product anyway.
+	var uuids []string
+	for _, id := range ids {
+		if uuid.Validate(id) == nil {
+			uuids = append(uuids, id)
+		}
+	}
+	if len(uuids) == 0 {
+		return nil, nil
+	}
 
-	err := row.Scan(&idStr, &name, &description, &price, &status)
+	query := "SELECT " + productColumns + " FROM products WHERE id = ANY($1) AND deleted_at IS NULL"
+	rows, err := conn(ctx, r.Db).QueryContext(ctx, query, pq.Array(uuids))
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("product with id %s not found", id)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []*entity.Product
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
 		}
+		products = append(products, product)
+	}
+	if err = rows.Err(); err != nil {
 		return nil, err
 	}
+	return products, nil
+}
+
+func (r *ProductRepository) Delete(ctx context.Context, id string) (*entity.Product, error) {
+	query := "UPDATE products SET deleted_at = now(), version = version + 1 WHERE id = $1 AND deleted_at IS NULL RETURNING " + productColumns
 
-	product, err = domain.NewProduct(name, description, price)
+	product, err := scanProduct(conn(ctx, r.Db).QueryRowContext(ctx, query, id))
 	if err != nil {
-		return nil, err
+		if err == sql.ErrNoRows {
+			return nil, entity.NewProductNotFoundError(id)
+		}
+		return nil, translateError(id, err)
 	}
+	return product, nil
+}
 
-	product.SetID(idStr)
+func (r *ProductRepository) Restore(ctx context.Context, id string) (*entity.Product, error) {
+	query := "UPDATE products SET deleted_at = NULL, version = version + 1 WHERE id = $1 AND deleted_at IS NOT NULL RETURNING " + productColumns
 
-	if status == domain.ENABLED {
-		err = product.Enable()
-	} else {
-		err = product.Disable()
+	product, err := scanProduct(conn(ctx, r.Db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		// Tell a missing product apart from one that is not in the trash
+		var exists bool
+		err = conn(ctx, r.Db).QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)", id).Scan(&exists)
+		if err != nil {
+			return nil, translateError(id, err)
+		}
+		if exists {
+			return nil, fmt.Errorf("%w: product with id %s is not deleted", entity.ErrConflict, id)
+		}
+		return nil, entity.NewProductNotFoundError(id)
 	}
 	if err != nil {
-		return nil, err
+		return nil, translateError(id, err)
 	}
-
 	return product, nil
 }
 
-func (r *ProductRepository) Delete(id string) error {
-	query := "DELETE FROM products WHERE id = $1"
-	result, err := r.Db.Exec(query, id)
+func (r *ProductRepository) ListDeleted(ctx context.Context, page, limit int) ([]*entity.Product, int, error) {
+	offset := (page - 1) * limit
+
+	var totalCount int
+	err := conn(ctx, r.Db).QueryRowContext(ctx, "SELECT COUNT(*) FROM products WHERE deleted_at IS NOT NULL").Scan(&totalCount)
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	query := "SELECT " + productColumns + " FROM products WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, id LIMIT $1 OFFSET $2"
+	rows, err := conn(ctx, r.Db).QueryContext(ctx, query, limit, offset)
 	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var products []*entity.Product
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		products = append(products, product)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return products, totalCount, nil
+}
+
+func (r *ProductRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) ([]*entity.Product, error) {
+	query := "DELETE FROM products WHERE deleted_at < $1 RETURNING " + productColumns
+	rows, err := conn(ctx, r.Db).QueryContext(ctx, query, deletedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []*entity.Product
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *ProductRepository) DeletePermanently(ctx context.Context, id string) (*entity.Product, error) {
+	query := "DELETE FROM products WHERE id = $1 RETURNING " + productColumns
+
+	product, err := scanProduct(conn(ctx, r.Db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.NewProductNotFoundError(id)
+		}
+		return nil, translateError(id, err)
+	}
+	return product, nil
+}
+
+// translateError maps Postgres errors to domain errors. A malformed UUID can never
+// match a row, so it is reported as not found instead of an internal error.
+// An empty id means the statement touched several products.
+func translateError(id string, err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
 		return err
 	}
+	switch {
+	case pqErr.Code == pqUniqueViolation && id == "":
+		return fmt.Errorf("%w: %s", entity.ErrConflict, pqErr.Detail)
+	case pqErr.Code == pqUniqueViolation:
+		return fmt.Errorf("%w: product with id %s already exists", entity.ErrConflict, id)
+	case pqErr.Code == pqInvalidTextRepresentation && id != "":
+		return entity.NewProductNotFoundError(id)
+	}
+	return err
+}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("product with id %s not found", id)
+// searchDocument is the text searched by ProductFilter.Query. It must match the
+// expression of the products_search_idx index so Postgres can use it.
+const searchDocument = "to_tsvector('simple', name || ' ' || coalesce(description, ''))"
+
+// productFilterClause builds the WHERE clause, including its leading space, and the
+// positional arguments for filter. Products in the trash are always left out.
+func productFilterClause(filter domain.ProductFilter) (string, []any) {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
 	}
 
-	return nil
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+	if filter.MinPrice != nil {
+		add("price >= $%d", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		add("price <= $%d", *filter.MaxPrice)
+	}
+	if filter.NamePrefix != "" {
+		add(`name ILIKE $%d ESCAPE '\'`, escapeLike(filter.NamePrefix)+"%")
+	}
+	if strings.TrimSpace(filter.Query) != "" {
+		add(searchDocument+" @@ plainto_tsquery('simple', $%d)", filter.Query)
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// escapeLike escapes the LIKE wildcards in s so it is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanProduct rebuilds a product from a row selecting productColumns.
+func scanProduct(row rowScanner) (*entity.Product, error) {
+	var id, name, status string
+	var description sql.NullString
+	var price float64
+	var createdAt time.Time
+	var version int
+	var deletedAt sql.NullTime
+
+	err := row.Scan(&id, &name, &description, &price, &status, &createdAt, &version, &deletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	var deletedAtTime time.Time
+	if deletedAt.Valid {
+		deletedAtTime = deletedAt.Time.UTC()
+	}
+	return entity.RehydrateProduct(id, name, description.String, price, status, createdAt.UTC(), version, deletedAtTime)
 }
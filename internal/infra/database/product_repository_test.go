@@ -1,12 +1,20 @@
 package database_test
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"testing"
+	"time"
 
+	"github.com/HaroldoFV/product-service/internal/domain"
 	"github.com/HaroldoFV/product-service/internal/domain/entity"
 	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/infra/events"
+	"github.com/HaroldoFV/product-service/internal/usecase"
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -16,52 +24,54 @@ type ProductRepositoryTestSuite struct {
 	suite.Suite
 	DB         *sql.DB
 	Repository *database.ProductRepository
+	Migrator   *database.Migrator
 }
 
 func (suite *ProductRepositoryTestSuite) SetupSuite() {
 	connectionString := "host=localhost port=5433 user=root_test password=root_test dbname=test_product_db sslmode=disable"
 	db, err := sql.Open("postgres", connectionString)
-	if err != nil {
-		log.Fatal(err)
+	suite.Require().NoError(err)
+	// The rest of the package does not need a database, so let it run without one
+	if err = db.Ping(); err != nil {
+		db.Close()
+		suite.T().Skipf("Postgres is not available: %v", err)
 	}
 	suite.DB = db
-	suite.Repository = database.NewProductRepository(db)
+	suite.Repository = database.NewProductRepository(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
 
-	// Create the products table
-	_, err = suite.DB.Exec(`
-		CREATE TABLE IF NOT EXISTS products (
-			id VARCHAR(36) PRIMARY KEY,
-			name VARCHAR(100) NOT NULL,
-			description VARCHAR(500),
-			price DECIMAL(10, 2) NOT NULL,
-			status VARCHAR(10) NOT NULL
-		)
-	`)
-	if err != nil {
-		log.Fatal(err)
-	}
+	// Create the schema from the same migrations the service runs
+	suite.Migrator, err = database.NewMigrator(db)
+	suite.Require().NoError(err)
+	_, err = suite.Migrator.Up()
+	suite.Require().NoError(err)
 }
 
 func (suite *ProductRepositoryTestSuite) TearDownSuite() {
-	_, err := suite.DB.Exec("DROP TABLE IF EXISTS products")
-	if err != nil {
-		log.Fatal(err)
+	if suite.DB == nil {
+		return
 	}
+	_, err := suite.Migrator.Down(0)
+	suite.Require().NoError(err)
 	suite.DB.Close()
 }
 
 func (suite *ProductRepositoryTestSuite) SetupTest() {
 	_, err := suite.DB.Exec("DELETE FROM products")
-	if err != nil {
-		log.Fatal(err)
-	}
+	suite.Require().NoError(err)
+	// The audit trail is append-only, so it can only be emptied by truncating it
+	_, err = suite.DB.Exec("TRUNCATE product_audit")
+	suite.Require().NoError(err)
+	_, err = suite.DB.Exec("DELETE FROM product_outbox")
+	suite.Require().NoError(err)
+	_, err = suite.DB.Exec("DELETE FROM webhooks")
+	suite.Require().NoError(err)
 }
 
 func (suite *ProductRepositoryTestSuite) TestCreateProduct() {
 	product, err := entity.NewProduct("Test Product", "Test Description", 10.0)
 	assert.NoError(suite.T(), err)
 
-	err = suite.Repository.Create(product)
+	err = suite.Repository.Create(context.Background(), product)
 	assert.NoError(suite.T(), err)
 
 	// Verify the product was created
@@ -76,11 +86,38 @@ func (suite *ProductRepositoryTestSuite) TestCreateProductWithInvalidData() {
 	assert.Error(suite.T(), err)
 
 	if product != nil {
-		err = suite.Repository.Create(product)
+		err = suite.Repository.Create(context.Background(), product)
 		assert.Error(suite.T(), err)
 	}
 }
 
+func (suite *ProductRepositoryTestSuite) TestCreateBatch() {
+	var products []*entity.Product
+	for i := 0; i < 3; i++ {
+		product, err := entity.NewProduct("Batch Product", "Batch Description", 10.0)
+		suite.Require().NoError(err)
+		products = append(products, product)
+	}
+
+	err := suite.Repository.CreateBatch(context.Background(), products)
+	suite.Require().NoError(err)
+
+	var count int
+	err = suite.DB.QueryRow("SELECT COUNT(*) FROM products").Scan(&count)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 3, count)
+
+	// A duplicated product rolls back the whole batch
+	newProduct, err := entity.NewProduct("New Product", "New Description", 10.0)
+	suite.Require().NoError(err)
+	err = suite.Repository.CreateBatch(context.Background(), []*entity.Product{newProduct, products[0]})
+	assert.ErrorIs(suite.T(), err, entity.ErrConflict)
+
+	err = suite.DB.QueryRow("SELECT COUNT(*) FROM products").Scan(&count)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 3, count)
+}
+
 func (suite *ProductRepositoryTestSuite) TestList() {
 	// Create test products
 	products := []struct {
@@ -98,7 +135,7 @@ func (suite *ProductRepositoryTestSuite) TestList() {
 	for _, p := range products {
 		product, err := entity.NewProduct(p.name, p.description, p.price)
 		assert.NoError(suite.T(), err)
-		err = suite.Repository.Create(product)
+		err = suite.Repository.Create(context.Background(), product)
 		assert.NoError(suite.T(), err)
 	}
 
@@ -114,31 +151,138 @@ func (suite *ProductRepositoryTestSuite) TestList() {
 		{"First page, default sort", 1, 3, "id", 3, 5},
 		{"Second page, default sort", 2, 3, "id", 2, 5},
 		{"All products, sort by price", 1, 10, "price", 5, 5},
-		{"Invalid sort field", 1, 5, "invalid", 5, 5}, // Should default to "id"
+		{"All products, sort by price descending", 1, 10, "-price", 5, 5},
+		{"All products, sort by creation time", 1, 10, "created_at", 5, 5},
 	}
 
 	for _, tc := range testCases {
 		suite.T().Run(tc.name, func(t *testing.T) {
-			resultProducts, totalCount, err := suite.Repository.List(tc.page, tc.limit, tc.sort)
+			sort, err := domain.ParseProductSort(tc.sort)
+			suite.Require().NoError(err)
+			resultProducts, totalCount, err := suite.Repository.List(context.Background(), domain.ProductFilter{}, tc.page, tc.limit, sort)
 
 			assert.NoError(t, err)
 			assert.Equal(t, tc.expectedCount, len(resultProducts))
 			assert.Equal(t, tc.expectedTotal, totalCount)
 
-			if tc.sort == "price" && len(resultProducts) > 1 {
-				for i := 1; i < len(resultProducts); i++ {
+			for i := 1; i < len(resultProducts); i++ {
+				switch tc.sort {
+				case "price":
 					assert.GreaterOrEqual(t, resultProducts[i].GetPrice(), resultProducts[i-1].GetPrice())
+				case "-price":
+					assert.LessOrEqual(t, resultProducts[i].GetPrice(), resultProducts[i-1].GetPrice())
+				case "created_at":
+					assert.False(t, resultProducts[i].GetCreatedAt().Before(resultProducts[i-1].GetCreatedAt()))
 				}
 			}
 		})
 	}
 }
 
+func (suite *ProductRepositoryTestSuite) TestListWithFilters() {
+	products := []struct {
+		name        string
+		description string
+		price       float64
+		enabled     bool
+	}{
+		{"Cadeira Gamer", "Cadeira ergonômica com apoio lombar", 999.99, true},
+		{"Cadeira de Escritório", "Cadeira com rodinhas", 499.90, false},
+		{"Teclado Mecânico", "Teclado RGB com switches azuis", 449.99, true},
+		{"Mouse Gamer", "Mouse com 7 botões programáveis", 299.99, false},
+		{"100% Algodão_Camiseta", "Camiseta básica", 49.90, true},
+	}
+
+	for _, p := range products {
+		product, err := entity.NewProduct(p.name, p.description, p.price)
+		suite.Require().NoError(err)
+		if p.enabled {
+			suite.Require().NoError(product.Enable())
+		}
+		suite.Require().NoError(suite.Repository.Create(context.Background(), product))
+	}
+
+	minPrice, maxPrice := 300.0, 1000.0
+
+	testCases := []struct {
+		name          string
+		filter        domain.ProductFilter
+		expectedNames []string
+	}{
+		{"By status", domain.ProductFilter{Status: entity.DISABLED}, []string{"Cadeira de Escritório", "Mouse Gamer"}},
+		{"By price range", domain.ProductFilter{MinPrice: &minPrice, MaxPrice: &maxPrice}, []string{"Cadeira Gamer", "Cadeira de Escritório", "Teclado Mecânico"}},
+		{"By name prefix", domain.ProductFilter{NamePrefix: "cadeira"}, []string{"Cadeira Gamer", "Cadeira de Escritório"}},
+		{"By name prefix with wildcards", domain.ProductFilter{NamePrefix: "100% Algodão_"}, []string{"100% Algodão_Camiseta"}},
+		{"Full-text search", domain.ProductFilter{Query: "gamer"}, []string{"Cadeira Gamer", "Mouse Gamer"}},
+		{"Full-text search over description", domain.ProductFilter{Query: "RGB switches"}, []string{"Teclado Mecânico"}},
+		{"Combined filters", domain.ProductFilter{Status: entity.ENABLED, Query: "cadeira"}, []string{"Cadeira Gamer"}},
+		{"No matches", domain.ProductFilter{Query: "monitor"}, nil},
+	}
+
+	for _, tc := range testCases {
+		suite.T().Run(tc.name, func(t *testing.T) {
+			resultProducts, totalCount, err := suite.Repository.List(context.Background(), tc.filter, 1, 2, domain.ProductSort{{Field: domain.SortByPrice}})
+			assert.NoError(t, err)
+			assert.Equal(t, len(tc.expectedNames), totalCount)
+
+			var names []string
+			err = suite.Repository.ForEach(context.Background(), tc.filter, func(product *entity.Product) error {
+				names = append(names, product.GetName())
+				return nil
+			})
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedNames, names)
+			assert.LessOrEqual(t, len(resultProducts), 2)
+		})
+	}
+}
+
+func (suite *ProductRepositoryTestSuite) TestListByCursor() {
+	prices := []float64{30.0, 10.0, 20.0, 10.0, 30.0}
+	for i, price := range prices {
+		product, err := entity.NewProduct(fmt.Sprintf("Product %d", i), "Description", price)
+		suite.Require().NoError(err)
+		suite.Require().NoError(suite.Repository.Create(context.Background(), product))
+	}
+
+	// Descending price with ascending names exercises keys sorted in different directions
+	sort, err := domain.ParseProductSort("-price,name")
+	suite.Require().NoError(err)
+	expected, _, err := suite.Repository.List(context.Background(), domain.ProductFilter{}, 1, 10, sort)
+	suite.Require().NoError(err)
+
+	// Walk forward two products at a time
+	var walked []*entity.Product
+	var cursor *domain.ProductCursor
+	for {
+		page, err := suite.Repository.ListByCursor(context.Background(), domain.ProductFilter{}, sort, cursor, 2)
+		suite.Require().NoError(err)
+		if len(page) == 0 {
+			break
+		}
+		walked = append(walked, page...)
+		next := domain.NewProductCursor(page[len(page)-1], sort, false)
+		cursor = &next
+	}
+	suite.Require().Len(walked, len(expected))
+	for i := range expected {
+		assert.Equal(suite.T(), expected[i].GetID(), walked[i].GetID())
+	}
+
+	// Walking backward from the last product returns the two products before it, in listing order
+	before := domain.NewProductCursor(expected[4], sort, true)
+	page, err := suite.Repository.ListByCursor(context.Background(), domain.ProductFilter{}, sort, &before, 2)
+	suite.Require().NoError(err)
+	suite.Require().Len(page, 2)
+	assert.Equal(suite.T(), expected[2].GetID(), page[0].GetID())
+	assert.Equal(suite.T(), expected[3].GetID(), page[1].GetID())
+}
+
 func (suite *ProductRepositoryTestSuite) TestUpdate() {
 	initialProduct, err := entity.NewProduct("Test Product", "Test Description", 10.0)
 	suite.Require().NoError(err)
 
-	err = suite.Repository.Create(initialProduct)
+	err = suite.Repository.Create(context.Background(), initialProduct)
 	suite.Require().NoError(err)
 
 	err = initialProduct.Update("Updated Product", "Updated Description")
@@ -147,22 +291,38 @@ func (suite *ProductRepositoryTestSuite) TestUpdate() {
 	err = initialProduct.ChangePrice(20.0)
 	suite.Require().NoError(err)
 
-	err = suite.Repository.Update(initialProduct)
+	err = initialProduct.Enable()
+	suite.Require().NoError(err)
+
+	err = suite.Repository.Update(context.Background(), initialProduct)
 	suite.Require().NoError(err)
 
-	updatedProduct, err := suite.Repository.GetByID(initialProduct.GetID())
+	updatedProduct, err := suite.Repository.GetByID(context.Background(), initialProduct.GetID())
 	suite.Require().NoError(err)
 
 	assert.Equal(suite.T(), "Updated Product", updatedProduct.GetName())
 	assert.Equal(suite.T(), "Updated Description", updatedProduct.GetDescription())
 	assert.Equal(suite.T(), 20.0, updatedProduct.GetPrice())
+	assert.Equal(suite.T(), entity.ENABLED, updatedProduct.GetStatus())
+	assert.Equal(suite.T(), 2, updatedProduct.GetVersion())
+	assert.Equal(suite.T(), 2, initialProduct.GetVersion())
+
+	stale := *updatedProduct
+	stale.SetVersion(1)
+	err = suite.Repository.Update(context.Background(), &stale)
+	assert.ErrorIs(suite.T(), err, entity.ErrConflict)
+
+	missing, err := entity.NewProduct("Missing Product", "Missing Description", 10.0)
+	suite.Require().NoError(err)
+	err = suite.Repository.Update(context.Background(), missing)
+	assert.ErrorIs(suite.T(), err, entity.ErrNotFound)
 }
 
 func (suite *ProductRepositoryTestSuite) TestGetByID() {
 	product, err := entity.NewProduct("Test Product", "Test Description", 10.0)
 	suite.Require().NoError(err)
 
-	err = suite.Repository.Create(product)
+	err = suite.Repository.Create(context.Background(), product)
 	suite.Require().NoError(err)
 
 	testCases := []struct {
@@ -176,7 +336,7 @@ func (suite *ProductRepositoryTestSuite) TestGetByID() {
 
 	for _, tc := range testCases {
 		suite.T().Run(tc.name, func(t *testing.T) {
-			retrievedProduct, err := suite.Repository.GetByID(tc.id)
+			retrievedProduct, err := suite.Repository.GetByID(context.Background(), tc.id)
 
 			if tc.expectedError {
 				assert.Error(t, err)
@@ -194,11 +354,35 @@ func (suite *ProductRepositoryTestSuite) TestGetByID() {
 	}
 }
 
+func (suite *ProductRepositoryTestSuite) TestGetByIDs() {
+	var ids []string
+	for _, name := range []string{"Product A", "Product B", "Product C"} {
+		product, err := entity.NewProduct(name, "description", 10.0)
+		suite.Require().NoError(err)
+		suite.Require().NoError(suite.Repository.Create(context.Background(), product))
+		ids = append(ids, product.GetID())
+	}
+	_, err := suite.Repository.Delete(context.Background(), ids[2])
+	suite.Require().NoError(err)
+
+	products, err := suite.Repository.GetByIDs(context.Background(), []string{ids[1], "non-existent-id", ids[0], ids[2]})
+	suite.Require().NoError(err)
+	var found []string
+	for _, product := range products {
+		found = append(found, product.GetID())
+	}
+	assert.ElementsMatch(suite.T(), []string{ids[0], ids[1]}, found)
+
+	products, err = suite.Repository.GetByIDs(context.Background(), []string{"non-existent-id"})
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), products)
+}
+
 func (suite *ProductRepositoryTestSuite) TestDelete() {
 	product, err := entity.NewProduct("Test Product", "Test Description", 10.0)
 	suite.Require().NoError(err)
 
-	err = suite.Repository.Create(product)
+	err = suite.Repository.Create(context.Background(), product)
 	suite.Require().NoError(err)
 
 	testCases := []struct {
@@ -212,18 +396,397 @@ func (suite *ProductRepositoryTestSuite) TestDelete() {
 
 	for _, tc := range testCases {
 		suite.T().Run(tc.name, func(t *testing.T) {
-			err := suite.Repository.Delete(tc.id)
+			_, err := suite.Repository.Delete(context.Background(), tc.id)
 
 			if tc.expectedError {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 
-				_, err := suite.Repository.GetByID(tc.id)
+				_, err := suite.Repository.GetByID(context.Background(), tc.id)
 				assert.Error(t, err)
+
+				_, err = suite.Repository.Delete(context.Background(), tc.id)
+				assert.ErrorIs(t, err, entity.ErrNotFound)
 			}
 		})
 	}
+
+	// Deleted products are hidden from the listings and cannot be updated
+	_, totalCount, err := suite.Repository.List(context.Background(), domain.ProductFilter{}, 1, 10, domain.ProductSort{{Field: domain.SortByID}})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, totalCount)
+	err = suite.Repository.Update(context.Background(), product)
+	assert.ErrorIs(suite.T(), err, entity.ErrNotFound)
+}
+
+func (suite *ProductRepositoryTestSuite) TestTrash() {
+	ctx := context.Background()
+	first, err := entity.NewProduct("First Product", "Description", 10.0)
+	suite.Require().NoError(err)
+	second, err := entity.NewProduct("Second Product", "Description", 20.0)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.Repository.CreateBatch(ctx, []*entity.Product{first, second}))
+
+	_, err = suite.Repository.Delete(ctx, first.GetID())
+	suite.Require().NoError(err)
+	time.Sleep(time.Millisecond)
+	_, err = suite.Repository.Delete(ctx, second.GetID())
+	suite.Require().NoError(err)
+
+	deleted, totalCount, err := suite.Repository.ListDeleted(ctx, 1, 10)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 2, totalCount)
+	suite.Require().Len(deleted, 2)
+	assert.Equal(suite.T(), second.GetID(), deleted[0].GetID(), "most recently deleted first")
+	assert.True(suite.T(), deleted[1].IsDeleted())
+	assert.Equal(suite.T(), 2, deleted[1].GetVersion())
+
+	restored, err := suite.Repository.Restore(ctx, first.GetID())
+	suite.Require().NoError(err)
+	assert.False(suite.T(), restored.IsDeleted())
+	assert.Equal(suite.T(), 3, restored.GetVersion())
+	_, err = suite.Repository.GetByID(ctx, first.GetID())
+	suite.Require().NoError(err)
+
+	_, err = suite.Repository.Restore(ctx, first.GetID())
+	assert.ErrorIs(suite.T(), err, entity.ErrConflict)
+	_, err = suite.Repository.Restore(ctx, "non-existent-id")
+	assert.ErrorIs(suite.T(), err, entity.ErrNotFound)
+
+	purged, err := suite.Repository.PurgeDeleted(ctx, time.Now().Add(-time.Hour))
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), purged)
+	purged, err = suite.Repository.PurgeDeleted(ctx, time.Now().Add(time.Second))
+	suite.Require().NoError(err)
+	suite.Require().Len(purged, 1)
+	assert.Equal(suite.T(), second.GetID(), purged[0].GetID())
+
+	removed, err := suite.Repository.DeletePermanently(ctx, first.GetID())
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "First Product", removed.GetName())
+	_, err = suite.Repository.DeletePermanently(ctx, first.GetID())
+	assert.ErrorIs(suite.T(), err, entity.ErrNotFound)
+}
+
+func (suite *ProductRepositoryTestSuite) TestAudit() {
+	ctx := context.Background()
+	repository := database.NewProductAuditRepository(suite.DB)
+	product, err := entity.NewProduct("Test Product", "Test Description", 10.0)
+	suite.Require().NoError(err)
+
+	created := entity.NewProductAuditEntry(product.GetID(), "alice", entity.AuditCreate, 1, entity.DiffProducts(nil, product))
+	updated := entity.NewProductAuditEntry(product.GetID(), "bob", entity.AuditUpdate, 2,
+		[]entity.FieldChange{{Field: entity.AuditFieldPrice, Before: 10.0, After: 12.5}})
+	suite.Require().NoError(repository.Append(ctx, created, updated))
+	suite.Require().NoError(repository.Append(ctx))
+
+	entries, totalCount, err := repository.ListByProduct(ctx, product.GetID(), 1, 10)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 2, totalCount)
+	suite.Require().Len(entries, 2)
+	assert.Equal(suite.T(), updated.ID, entries[0].ID, "last appended first")
+	assert.Equal(suite.T(), "bob", entries[0].Actor)
+	assert.Equal(suite.T(), entity.AuditUpdate, entries[0].Operation)
+	assert.Equal(suite.T(), 2, entries[0].Version)
+	assert.Equal(suite.T(), updated.Changes, entries[0].Changes)
+	assert.True(suite.T(), updated.CreatedAt.Equal(entries[0].CreatedAt))
+	assert.Len(suite.T(), entries[1].Changes, 5)
+
+	entries, _, err = repository.ListByProduct(ctx, product.GetID(), 2, 1)
+	suite.Require().NoError(err)
+	suite.Require().Len(entries, 1)
+	assert.Equal(suite.T(), created.ID, entries[0].ID)
+
+	_, totalCount, err = repository.ListByProduct(ctx, "non-existent-id", 1, 10)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, totalCount)
+
+	// Entries cannot be changed or removed
+	_, err = suite.DB.Exec("UPDATE product_audit SET actor = 'mallory'")
+	assert.ErrorContains(suite.T(), err, "cannot be modified")
+	_, err = suite.DB.Exec("DELETE FROM product_audit")
+	assert.ErrorContains(suite.T(), err, "cannot be modified")
+}
+
+func (suite *ProductRepositoryTestSuite) TestTransactionManager() {
+	ctx := context.Background()
+	manager := database.NewTransactionManager(suite.DB)
+	audit := database.NewProductAuditRepository(suite.DB)
+	product, err := entity.NewProduct("Test Product", "Test Description", 10.0)
+	suite.Require().NoError(err)
+
+	// A failed audit append rolls back the product written in the same transaction
+	err = manager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := suite.Repository.Create(ctx, product); err != nil {
+			return err
+		}
+		return audit.Append(ctx, entity.NewProductAuditEntry("not-a-uuid", "alice", entity.AuditCreate, 1, nil))
+	})
+	suite.Require().Error(err)
+	_, err = suite.Repository.GetByID(ctx, product.GetID())
+	assert.ErrorIs(suite.T(), err, entity.ErrNotFound)
+
+	// Nested calls join the outer transaction
+	err = manager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := suite.Repository.Create(ctx, product); err != nil {
+			return err
+		}
+		return manager.WithinTransaction(ctx, func(ctx context.Context) error {
+			return audit.Append(ctx, entity.NewProductAuditEntry(product.GetID(), "alice", entity.AuditCreate, 1, nil))
+		})
+	})
+	suite.Require().NoError(err)
+	_, err = suite.Repository.GetByID(ctx, product.GetID())
+	suite.Require().NoError(err)
+	_, totalCount, err := audit.ListByProduct(ctx, product.GetID(), 1, 10)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, totalCount)
+}
+
+func (suite *ProductRepositoryTestSuite) TestOutbox() {
+	ctx := context.Background()
+	repository := database.NewProductOutboxRepository(suite.DB)
+	product, err := entity.NewProduct("Test Product", "Test Description", 10.0)
+	suite.Require().NoError(err)
+	suite.Require().NoError(product.ChangePrice(12.5))
+	events := product.PullEvents()
+	suite.Require().NoError(repository.Append(ctx, events...))
+
+	now := time.Now()
+	pending, err := repository.ClaimPending(ctx, now, time.Minute, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(pending, 2)
+	assert.Equal(suite.T(), events[0].ID, pending[0].ID, "oldest first")
+	assert.Equal(suite.T(), entity.PriceChanged, pending[1].Type)
+	assert.Equal(suite.T(), product.GetID(), pending[1].ProductID)
+	assert.Equal(suite.T(), 1, pending[1].Version)
+	assert.Equal(suite.T(), events[1].Data, pending[1].Data)
+	assert.True(suite.T(), events[1].OccurredAt.Equal(pending[1].OccurredAt))
+
+	// Claimed events are skipped by concurrent relays until their claim ends
+	others, err := repository.ClaimPending(ctx, now, time.Minute, 10)
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), others)
+	suite.Require().NoError(repository.MarkPublished(ctx, events[0].ID))
+	suite.Require().NoError(repository.Release(ctx, events[1].ID))
+	pending, err = repository.ClaimPending(ctx, now, time.Minute, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(pending, 1)
+	assert.Equal(suite.T(), events[1].ID, pending[0].ID)
+	pending, err = repository.ClaimPending(ctx, now.Add(time.Minute), time.Minute, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(pending, 1, "expired claims can be taken over")
+
+	dead, err := repository.MarkFailed(ctx, events[1].ID, "broker unavailable", 2)
+	suite.Require().NoError(err)
+	assert.False(suite.T(), dead)
+	pending, err = repository.ClaimPending(ctx, now, time.Minute, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(pending, 1, "failed events stay pending")
+
+	var attempts int
+	var lastError string
+	suite.Require().NoError(suite.DB.QueryRow("SELECT attempts, last_error FROM product_outbox WHERE id = $1", events[1].ID).Scan(&attempts, &lastError))
+	assert.Equal(suite.T(), 1, attempts)
+	assert.Equal(suite.T(), "broker unavailable", lastError)
+
+	dead, err = repository.MarkFailed(ctx, events[1].ID, "broker unavailable", 2)
+	suite.Require().NoError(err)
+	assert.True(suite.T(), dead)
+	pending, err = repository.ClaimPending(ctx, now, time.Minute, 10)
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), pending, "dead events are no longer pending")
+	suite.Require().NoError(suite.DB.QueryRow("SELECT attempts FROM product_outbox WHERE id = $1", events[1].ID).Scan(&attempts))
+	assert.Equal(suite.T(), 2, attempts)
+
+	dead, err = repository.MarkFailed(ctx, "00000000-0000-0000-0000-000000000000", "broker unavailable", 1)
+	suite.Require().NoError(err)
+	assert.False(suite.T(), dead, "unknown events are ignored")
+}
+
+func (suite *ProductRepositoryTestSuite) TestOutboxAfterUpdate() {
+	ctx := context.Background()
+	product, err := entity.NewProduct("Test Product", "Test Description", 10.0)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.Repository.Create(ctx, product))
+
+	outboxRepository := database.NewProductOutboxRepository(suite.DB)
+	updateProduct := usecase.NewUpdateProductUseCase(
+		suite.Repository,
+		database.NewProductAuditRepository(suite.DB),
+		outboxRepository,
+		database.NewTransactionManager(suite.DB),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	_, err = updateProduct.Execute(ctx, usecase.ProductUpdateInputDTO{
+		ID:          product.GetID(),
+		Name:        "Updated Product",
+		Description: "Test Description",
+		Price:       10.0,
+	})
+	suite.Require().NoError(err)
+
+	// Reading the product back must not raise a product.created event of its own
+	pending, err := outboxRepository.ClaimPending(ctx, time.Now(), time.Minute, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(pending, 1)
+	assert.Equal(suite.T(), entity.ProductUpdated, pending[0].Type)
+	assert.Equal(suite.T(), product.GetID(), pending[0].ProductID)
+	assert.Equal(suite.T(), 2, pending[0].Version)
+}
+
+// abortingWebhookRepository fails to create deliveries after running a statement that aborts
+// the transaction it is called in.
+type abortingWebhookRepository struct {
+	*database.WebhookRepository
+}
+
+func (r abortingWebhookRepository) CreateDeliveries(ctx context.Context, deliveries ...*entity.WebhookDelivery) error {
+	_, _ = r.GetDelivery(ctx, "not a uuid")
+	return errors.New("database unavailable")
+}
+
+func (suite *ProductRepositoryTestSuite) TestOutboxWithFailingWebhooks() {
+	ctx := context.Background()
+	outboxRepository := database.NewProductOutboxRepository(suite.DB)
+	product, err := entity.NewProduct("Test Product", "Test Description", 10.0)
+	suite.Require().NoError(err)
+	event := product.PullEvents()[0]
+	suite.Require().NoError(outboxRepository.Append(ctx, event))
+
+	manager := database.NewTransactionManager(suite.DB)
+	enqueue := usecase.NewEnqueueWebhookDeliveriesUseCase(abortingWebhookRepository{database.NewWebhookRepository(suite.DB)}, manager)
+	publish := usecase.NewPublishProductEventsUseCase(outboxRepository, events.PublisherFunc(enqueue.Execute), 2, time.Minute,
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// Every failed attempt is counted, until the event is dead
+	var attempts int
+	var dead bool
+	for i := 1; i <= 2; i++ {
+		_, err = publish.Execute(ctx, 10)
+		if i == 1 {
+			suite.Require().ErrorContains(err, "database unavailable")
+		} else {
+			suite.Require().NoError(err)
+		}
+		suite.Require().NoError(suite.DB.QueryRow("SELECT attempts, dead_at IS NOT NULL FROM product_outbox WHERE id = $1",
+			event.ID).Scan(&attempts, &dead))
+		assert.Equal(suite.T(), i, attempts)
+	}
+	assert.True(suite.T(), dead)
+}
+
+func (suite *ProductRepositoryTestSuite) TestMigrations() {
+	migrations := suite.Migrator.Migrations()
+	last := migrations[len(migrations)-1]
+
+	statuses, err := suite.Migrator.Status()
+	suite.Require().NoError(err)
+	suite.Require().Len(statuses, len(migrations))
+	for _, status := range statuses {
+		assert.True(suite.T(), status.Applied, "migration %d should be applied", status.Version)
+		assert.False(suite.T(), status.Dirty)
+	}
+
+	reverted, err := suite.Migrator.Down(1)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, reverted)
+	statuses, err = suite.Migrator.Status()
+	suite.Require().NoError(err)
+	assert.False(suite.T(), statuses[len(statuses)-1].Applied)
+	assert.Error(suite.T(), suite.Migrator.CheckVersion(context.Background()))
+
+	applied, err := suite.Migrator.Up()
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, applied)
+	assert.NoError(suite.T(), suite.Migrator.CheckVersion(context.Background()))
+
+	// Running again is a no-op
+	applied, err = suite.Migrator.Up()
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, applied)
+
+	var version uint
+	suite.Require().NoError(suite.DB.QueryRow("SELECT version FROM schema_migrations").Scan(&version))
+	assert.Equal(suite.T(), last.Version, version)
+}
+
+func (suite *ProductRepositoryTestSuite) TestWebhooks() {
+	ctx := context.Background()
+	repository := database.NewWebhookRepository(suite.DB)
+	manager := database.NewTransactionManager(suite.DB)
+	webhook, err := entity.NewWebhook("https://example.com/hooks", []string{entity.ProductCreated, entity.PriceChanged}, "0123456789abcdef")
+	suite.Require().NoError(err)
+	suite.Require().NoError(repository.Create(ctx, webhook))
+
+	stored, err := repository.GetByID(ctx, webhook.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), webhook, stored)
+	_, err = repository.GetByID(ctx, "not-a-uuid")
+	assert.ErrorIs(suite.T(), err, entity.ErrNotFound)
+	webhooks, err := repository.List(ctx)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), []*entity.Webhook{webhook}, webhooks)
+
+	product, err := entity.NewProduct("Test Product", "Test Description", 10.0)
+	suite.Require().NoError(err)
+	suite.Require().NoError(product.ChangePrice(12.5))
+	events := product.PullEvents()
+	first := entity.NewWebhookDelivery(webhook.ID, events[0])
+	second := entity.NewWebhookDelivery(webhook.ID, events[1])
+	suite.Require().NoError(repository.CreateDeliveries(ctx, first, second))
+
+	// Deliveries claimed within a transaction are skipped by concurrent dispatchers until it ends
+	now := time.Now().UTC()
+	err = manager.WithinTransaction(ctx, func(ctx context.Context) error {
+		claimed, err := repository.ClaimDueDeliveries(ctx, now, time.Minute, 1)
+		suite.Require().NoError(err)
+		suite.Require().Len(claimed, 1)
+		assert.Equal(suite.T(), first.ID, claimed[0].ID)
+		assert.Equal(suite.T(), first.Event.Data, claimed[0].Event.Data)
+
+		others, err := repository.ClaimDueDeliveries(context.Background(), now, time.Minute, 10)
+		suite.Require().NoError(err)
+		suite.Require().Len(others, 1)
+		assert.Equal(suite.T(), second.ID, others[0].ID)
+		return nil
+	})
+	suite.Require().NoError(err)
+
+	// Claimed deliveries are leased
+	claimed, err := repository.ClaimDueDeliveries(ctx, now.Add(30*time.Second), time.Minute, 10)
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), claimed)
+	claimed, err = repository.ClaimDueDeliveries(ctx, now.Add(2*time.Minute), time.Minute, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(claimed, 2)
+
+	claimed[0].Failed(0, "connection refused", false, time.Time{})
+	suite.Require().NoError(repository.UpdateDelivery(ctx, claimed[0]))
+	claimed[1].Succeeded(204, now.Truncate(time.Microsecond))
+	suite.Require().NoError(repository.UpdateDelivery(ctx, claimed[1]))
+	delivery, err := repository.GetDelivery(ctx, second.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), entity.DeliveryDelivered, delivery.Status)
+	assert.Equal(suite.T(), 204, delivery.LastStatusCode)
+	assert.True(suite.T(), delivery.DeliveredAt.Equal(now.Truncate(time.Microsecond)))
+
+	dead, totalCount, err := repository.ListDeliveries(ctx, domain.WebhookDeliveryFilter{Status: entity.DeliveryDead}, 1, 10)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, totalCount)
+	assert.Equal(suite.T(), first.ID, dead[0].ID)
+	assert.Equal(suite.T(), "connection refused", dead[0].LastError)
+	deliveries, totalCount, err := repository.ListDeliveries(ctx, domain.WebhookDeliveryFilter{WebhookID: webhook.ID}, 1, 1)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 2, totalCount)
+	assert.Equal(suite.T(), second.ID, deliveries[0].ID, "most recent first")
+
+	// Deleting a webhook deletes its deliveries
+	suite.Require().NoError(repository.Delete(ctx, webhook.ID))
+	assert.ErrorIs(suite.T(), repository.Delete(ctx, webhook.ID), entity.ErrNotFound)
+	_, err = repository.GetDelivery(ctx, first.ID)
+	assert.ErrorIs(suite.T(), err, entity.ErrNotFound)
 }
 
 func TestProductRepositoryTestSuite(t *testing.T) {
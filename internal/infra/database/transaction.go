@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+type txContextKey struct{}
+
+// TransactionManager implements domain.TransactionManager on top of a *sql.DB, storing the
+// transaction in the context given to the repositories.
+type TransactionManager struct {
+	Db *sql.DB
+}
+
+func NewTransactionManager(db *sql.DB) *TransactionManager {
+	return &TransactionManager{Db: db}
+}
+
+func (m *TransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txContextKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rolling back a committed transaction is a no-op
+	defer tx.Rollback()
+
+	if err = fn(context.WithValue(ctx, txContextKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// executor is implemented by both *sql.DB and *sql.Tx.
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// conn returns the transaction started by TransactionManager.WithinTransaction in ctx, if any,
+// or db otherwise.
+func conn(ctx context.Context, db *sql.DB) executor {
+	if tx, ok := ctx.Value(txContextKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// InMemoryTransactionManager implements domain.TransactionManager for the in-memory
+// repositories, which cannot roll back: fn is simply called unless ctx is already done. The writes
+// fn made before failing are kept, e.g. a product stored before ctx is canceled and the audit or
+// outbox repository refuses to append, so in-memory mode does not guarantee atomicity.
+type InMemoryTransactionManager struct{}
+
+func NewInMemoryTransactionManager() *InMemoryTransactionManager {
+	return &InMemoryTransactionManager{}
+}
+
+func (m *InMemoryTransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return fn(ctx)
+}
@@ -0,0 +1,213 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+)
+
+// InMemoryWebhookRepository is a thread-safe, non-persistent implementation of
+// WebhookRepositoryInterface.
+type InMemoryWebhookRepository struct {
+	mu       sync.RWMutex
+	webhooks []entity.Webhook
+	// deliveries are kept in the order they were created.
+	deliveries []entity.WebhookDelivery
+}
+
+func NewInMemoryWebhookRepository() *InMemoryWebhookRepository {
+	return &InMemoryWebhookRepository{}
+}
+
+func copyWebhook(webhook entity.Webhook) *entity.Webhook {
+	webhook.Events = slices.Clone(webhook.Events)
+	return &webhook
+}
+
+func copyDelivery(delivery entity.WebhookDelivery) *entity.WebhookDelivery {
+	delivery.Event.Data = maps.Clone(delivery.Event.Data)
+	return &delivery
+}
+
+func (r *InMemoryWebhookRepository) Create(ctx context.Context, webhook *entity.Webhook) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stored := range r.webhooks {
+		if stored.ID == webhook.ID {
+			return fmt.Errorf("%w: webhook with id %s already exists", entity.ErrConflict, webhook.ID)
+		}
+	}
+	r.webhooks = append(r.webhooks, *copyWebhook(*webhook))
+	return nil
+}
+
+func (r *InMemoryWebhookRepository) GetByID(ctx context.Context, id string) (*entity.Webhook, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, webhook := range r.webhooks {
+		if webhook.ID == id {
+			return copyWebhook(webhook), nil
+		}
+	}
+	return nil, webhookNotFound(id)
+}
+
+func (r *InMemoryWebhookRepository) List(ctx context.Context) ([]*entity.Webhook, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhooks := make([]*entity.Webhook, 0, len(r.webhooks))
+	for _, webhook := range r.webhooks {
+		webhooks = append(webhooks, copyWebhook(webhook))
+	}
+	return webhooks, nil
+}
+
+func (r *InMemoryWebhookRepository) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.webhooks, func(webhook entity.Webhook) bool { return webhook.ID == id })
+	if i < 0 {
+		return webhookNotFound(id)
+	}
+	r.webhooks = slices.Delete(r.webhooks, i, i+1)
+	r.deliveries = slices.DeleteFunc(r.deliveries, func(delivery entity.WebhookDelivery) bool {
+		return delivery.WebhookID == id
+	})
+	return nil
+}
+
+func (r *InMemoryWebhookRepository) CreateDeliveries(ctx context.Context, deliveries ...*entity.WebhookDelivery) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range deliveries {
+		r.deliveries = append(r.deliveries, *copyDelivery(*delivery))
+	}
+	return nil
+}
+
+func (r *InMemoryWebhookRepository) GetDelivery(ctx context.Context, id string) (*entity.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, delivery := range r.deliveries {
+		if delivery.ID == id {
+			return copyDelivery(delivery), nil
+		}
+	}
+	return nil, deliveryNotFound(id)
+}
+
+func (r *InMemoryWebhookRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, stored := range r.deliveries {
+		if stored.ID == delivery.ID {
+			r.deliveries[i] = *copyDelivery(*delivery)
+			return nil
+		}
+	}
+	return deliveryNotFound(delivery.ID)
+}
+
+func (r *InMemoryWebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []int
+	for i, delivery := range r.deliveries {
+		if delivery.Status == entity.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, i)
+		}
+	}
+	// Stable, so that deliveries due at the same time keep the order they were created in
+	slices.SortStableFunc(due, func(a, b int) int {
+		return r.deliveries[a].NextAttemptAt.Compare(r.deliveries[b].NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	slices.Sort(due)
+
+	deliveries := make([]*entity.WebhookDelivery, len(due))
+	for j, i := range due {
+		r.deliveries[i].NextAttemptAt = now.Add(lease)
+		deliveries[j] = copyDelivery(r.deliveries[i])
+	}
+	return deliveries, nil
+}
+
+func (r *InMemoryWebhookRepository) ListDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter, page, limit int) ([]*entity.WebhookDelivery, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// Most recent first: deliveries are stored in the order they were created
+	r.mu.RLock()
+	var deliveries []*entity.WebhookDelivery
+	for i := len(r.deliveries) - 1; i >= 0; i-- {
+		delivery := r.deliveries[i]
+		if (filter.WebhookID == "" || delivery.WebhookID == filter.WebhookID) &&
+			(filter.Status == "" || delivery.Status == filter.Status) {
+			deliveries = append(deliveries, copyDelivery(delivery))
+		}
+	}
+	r.mu.RUnlock()
+
+	totalCount := len(deliveries)
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= totalCount {
+		return nil, totalCount, nil
+	}
+	end := offset + limit
+	if end > totalCount {
+		end = totalCount
+	}
+	return deliveries[offset:end], totalCount, nil
+}
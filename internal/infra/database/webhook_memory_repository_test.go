@@ -0,0 +1,91 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryWebhookRepository(t *testing.T) {
+	repository := database.NewInMemoryWebhookRepository()
+	ctx := context.Background()
+
+	webhook, err := entity.NewWebhook("https://example.com/hooks", []string{entity.ProductCreated}, "0123456789abcdef")
+	require.NoError(t, err)
+	other, err := entity.NewWebhook("https://example.org/hooks", nil, "0123456789abcdef")
+	require.NoError(t, err)
+	require.NoError(t, repository.Create(ctx, webhook))
+	require.NoError(t, repository.Create(ctx, other))
+	require.ErrorIs(t, repository.Create(ctx, webhook), entity.ErrConflict)
+
+	stored, err := repository.GetByID(ctx, webhook.ID)
+	require.NoError(t, err)
+	require.Equal(t, webhook, stored)
+	webhooks, err := repository.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []*entity.Webhook{webhook, other}, webhooks)
+
+	product, err := entity.NewProduct("Product", "description", 10)
+	require.NoError(t, err)
+	require.NoError(t, product.ChangePrice(12))
+	events := product.PullEvents()
+	first := entity.NewWebhookDelivery(webhook.ID, events[0])
+	second := entity.NewWebhookDelivery(other.ID, events[0])
+	third := entity.NewWebhookDelivery(other.ID, events[1])
+	third.NextAttemptAt = third.NextAttemptAt.Add(time.Hour)
+	require.NoError(t, repository.CreateDeliveries(ctx, first, second, third))
+
+	// Only the due deliveries are claimed, and not again until their lease expires
+	now := time.Now().UTC()
+	claimed, err := repository.ClaimDueDeliveries(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	require.Equal(t, first.ID, claimed[0].ID)
+	require.Equal(t, second.ID, claimed[1].ID)
+	claimed, err = repository.ClaimDueDeliveries(ctx, now.Add(30*time.Second), time.Minute, 10)
+	require.NoError(t, err)
+	require.Empty(t, claimed)
+	claimed, err = repository.ClaimDueDeliveries(ctx, now.Add(2*time.Minute), time.Minute, 1)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.Equal(t, first.ID, claimed[0].ID)
+
+	claimed[0].Succeeded(200, now)
+	require.NoError(t, repository.UpdateDelivery(ctx, claimed[0]))
+	delivery, err := repository.GetDelivery(ctx, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.DeliveryDelivered, delivery.Status)
+	_, err = repository.GetDelivery(ctx, "missing")
+	require.ErrorIs(t, err, entity.ErrNotFound)
+
+	// Most recent first
+	deliveries, totalCount, err := repository.ListDeliveries(ctx, domain.WebhookDeliveryFilter{WebhookID: other.ID}, 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, 2, totalCount)
+	require.Equal(t, third.ID, deliveries[0].ID)
+	deliveries, totalCount, err = repository.ListDeliveries(ctx, domain.WebhookDeliveryFilter{Status: entity.DeliveryDelivered}, 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, totalCount)
+	require.Equal(t, first.ID, deliveries[0].ID)
+
+	// Deliveries cannot be changed through the values returned
+	deliveries[0].Event.Data["price"] = 0.0
+	delivery, err = repository.GetDelivery(ctx, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, 10.0, delivery.Event.Data["price"])
+
+	// Deleting a webhook deletes its deliveries
+	require.NoError(t, repository.Delete(ctx, other.ID))
+	require.ErrorIs(t, repository.Delete(ctx, other.ID), entity.ErrNotFound)
+	_, err = repository.GetByID(ctx, other.ID)
+	require.ErrorIs(t, err, entity.ErrNotFound)
+	_, totalCount, err = repository.ListDeliveries(ctx, domain.WebhookDeliveryFilter{}, 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, totalCount)
+	require.ErrorIs(t, repository.UpdateDelivery(ctx, third), entity.ErrNotFound)
+}
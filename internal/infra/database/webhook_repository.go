@@ -0,0 +1,327 @@
+package database
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/lib/pq"
+)
+
+// webhookDeliveryColumns lists the columns read by scanWebhookDelivery, in order.
+const webhookDeliveryColumns = "id, webhook_id, event_id, event_type, product_id, version, data, occurred_at, status, " +
+	"attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at"
+
+type WebhookRepository struct {
+	Db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{Db: db}
+}
+
+// webhookNotFound is returned for unknown webhooks, including ids that are not valid UUIDs.
+func webhookNotFound(id string) error {
+	return fmt.Errorf("%w: webhook with id %s not found", entity.ErrNotFound, id)
+}
+
+// deliveryNotFound is returned for unknown deliveries, including ids that are not valid UUIDs.
+func deliveryNotFound(id string) error {
+	return fmt.Errorf("%w: delivery with id %s not found", entity.ErrNotFound, id)
+}
+
+func isInvalidUUID(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqInvalidTextRepresentation
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook *entity.Webhook) error {
+	_, err := conn(ctx, r.Db).ExecContext(ctx,
+		"INSERT INTO webhooks (id, url, events, secret, created_at) VALUES ($1, $2, $3, $4, $5)",
+		webhook.ID, webhook.URL, pq.Array(nonNil(webhook.Events)), webhook.Secret, webhook.CreatedAt)
+	return translateError("", err)
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*entity.Webhook, error) {
+	row := conn(ctx, r.Db).QueryRowContext(ctx, "SELECT id, url, events, secret, created_at FROM webhooks WHERE id = $1", id)
+	webhook, err := scanWebhook(row)
+	if errors.Is(err, sql.ErrNoRows) || isInvalidUUID(err) {
+		return nil, webhookNotFound(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (r *WebhookRepository) List(ctx context.Context) ([]*entity.Webhook, error) {
+	rows, err := conn(ctx, r.Db).QueryContext(ctx, "SELECT id, url, events, secret, created_at FROM webhooks ORDER BY created_at, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*entity.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// Delete relies on the foreign key of webhook_deliveries to remove the deliveries of the webhook.
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.Db).ExecContext(ctx, "DELETE FROM webhooks WHERE id = $1", id)
+	if isInvalidUUID(err) {
+		return webhookNotFound(id)
+	}
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return webhookNotFound(id)
+	}
+	return nil
+}
+
+// CreateDeliveries inserts deliveries with multi-row INSERT statements of at most batchSize rows
+// each, inside the transaction in ctx if any.
+func (r *WebhookRepository) CreateDeliveries(ctx context.Context, deliveries ...*entity.WebhookDelivery) error {
+	const columns = 11
+	for start := 0; start < len(deliveries); start += batchSize {
+		end := start + batchSize
+		if end > len(deliveries) {
+			end = len(deliveries)
+		}
+		batch := deliveries[start:end]
+
+		var query strings.Builder
+		query.WriteString("INSERT INTO webhook_deliveries (id, webhook_id, event_id, event_type, product_id, version, data, " +
+			"occurred_at, status, next_attempt_at, created_at) VALUES ")
+		args := make([]any, 0, len(batch)*columns)
+		for i, delivery := range batch {
+			data, err := json.Marshal(delivery.Event.Data)
+			if err != nil {
+				return err
+			}
+
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			query.WriteString("(")
+			for j := 1; j <= columns; j++ {
+				if j > 1 {
+					query.WriteString(", ")
+				}
+				fmt.Fprintf(&query, "$%d", i*columns+j)
+			}
+			query.WriteString(")")
+			args = append(args, delivery.ID, delivery.WebhookID, delivery.Event.ID, delivery.Event.Type, delivery.Event.ProductID,
+				delivery.Event.Version, data, delivery.Event.OccurredAt, delivery.Status, delivery.NextAttemptAt, delivery.CreatedAt)
+		}
+
+		_, err := conn(ctx, r.Db).ExecContext(ctx, query.String(), args...)
+		if err != nil {
+			return translateError("", err)
+		}
+	}
+	return nil
+}
+
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id string) (*entity.WebhookDelivery, error) {
+	row := conn(ctx, r.Db).QueryRowContext(ctx, "SELECT "+webhookDeliveryColumns+" FROM webhook_deliveries WHERE id = $1", id)
+	delivery, err := scanWebhookDelivery(row)
+	if errors.Is(err, sql.ErrNoRows) || isInvalidUUID(err) {
+		return nil, deliveryNotFound(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// UpdateDelivery stores the outcome of the attempts of delivery.
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	result, err := conn(ctx, r.Db).ExecContext(ctx,
+		"UPDATE webhook_deliveries SET status = $2, attempts = $3, next_attempt_at = $4, last_status_code = $5, "+
+			"last_error = $6, delivered_at = $7 WHERE id = $1",
+		delivery.ID, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, nullInt(delivery.LastStatusCode),
+		nullString(delivery.LastError), nullTime(delivery.DeliveredAt))
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return deliveryNotFound(delivery.ID)
+	}
+	return nil
+}
+
+// ClaimDueDeliveries selects the due deliveries with FOR UPDATE SKIP LOCKED and postpones them in
+// the same statement, so that several instances of the service can dispatch deliveries.
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error) {
+	query := "UPDATE webhook_deliveries SET next_attempt_at = $2 WHERE id IN (" +
+		"SELECT id FROM webhook_deliveries WHERE status = 'pending' AND next_attempt_at <= $1 " +
+		"ORDER BY next_attempt_at, seq LIMIT $3 FOR UPDATE SKIP LOCKED) " +
+		"RETURNING " + webhookDeliveryColumns + ", seq"
+	rows, err := conn(ctx, r.Db).QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// RETURNING does not keep the order of the subquery
+	type claimed struct {
+		delivery *entity.WebhookDelivery
+		seq      int64
+	}
+	var all []claimed
+	for rows.Next() {
+		var c claimed
+		c.delivery, err = scanWebhookDelivery(rows, &c.seq)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*entity.WebhookDelivery, len(all))
+	slices.SortFunc(all, func(a, b claimed) int { return cmp.Compare(a.seq, b.seq) })
+	for i, c := range all {
+		deliveries[i] = c.delivery
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter, page, limit int) ([]*entity.WebhookDelivery, int, error) {
+	offset := (page - 1) * limit
+
+	var conditions []string
+	var args []any
+	if filter.WebhookID != "" {
+		args = append(args, filter.WebhookID)
+		conditions = append(conditions, fmt.Sprintf("webhook_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var totalCount int
+	err := conn(ctx, r.Db).QueryRowContext(ctx, "SELECT COUNT(*) FROM webhook_deliveries"+where, args...).Scan(&totalCount)
+	if isInvalidUUID(err) {
+		return nil, 0, webhookNotFound(filter.WebhookID)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM webhook_deliveries%s ORDER BY seq DESC LIMIT $%d OFFSET $%d",
+		webhookDeliveryColumns, where, len(args)+1, len(args)+2)
+	rows, err := conn(ctx, r.Db).QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var deliveries []*entity.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return deliveries, totalCount, nil
+}
+
+func scanWebhook(row rowScanner) (*entity.Webhook, error) {
+	var webhook entity.Webhook
+	var events pq.StringArray
+	err := row.Scan(&webhook.ID, &webhook.URL, &events, &webhook.Secret, &webhook.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) > 0 {
+		webhook.Events = events
+	}
+	webhook.CreatedAt = webhook.CreatedAt.UTC()
+	return &webhook, nil
+}
+
+// scanWebhookDelivery reads the columns listed in webhookDeliveryColumns, followed by extra.
+func scanWebhookDelivery(row rowScanner, extra ...any) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	var data []byte
+	var lastStatusCode sql.NullInt64
+	var lastError sql.NullString
+	var deliveredAt sql.NullTime
+	dest := []any{&delivery.ID, &delivery.WebhookID, &delivery.Event.ID, &delivery.Event.Type, &delivery.Event.ProductID,
+		&delivery.Event.Version, &data, &delivery.Event.OccurredAt, &delivery.Status, &delivery.Attempts,
+		&delivery.NextAttemptAt, &lastStatusCode, &lastError, &delivery.CreatedAt, &deliveredAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &delivery.Event.Data); err != nil {
+		return nil, err
+	}
+	delivery.LastStatusCode = int(lastStatusCode.Int64)
+	delivery.LastError = lastError.String
+	delivery.Event.OccurredAt = delivery.Event.OccurredAt.UTC()
+	delivery.NextAttemptAt = delivery.NextAttemptAt.UTC()
+	delivery.CreatedAt = delivery.CreatedAt.UTC()
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = deliveredAt.Time.UTC()
+	}
+	return &delivery, nil
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func nullInt(value int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(value), Valid: value != 0}
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func nullTime(value time.Time) sql.NullTime {
+	return sql.NullTime{Time: value, Valid: !value.IsZero()}
+}
@@ -0,0 +1,139 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+)
+
+// Publishers accepted by NewPublisher.
+const (
+	PublisherLog  = "log"
+	PublisherFile = "file"
+)
+
+// Message is the JSON representation of a product event sent to other services.
+type Message struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	ProductID  string         `json:"product_id"`
+	Version    int            `json:"version"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	Data       map[string]any `json:"data"`
+}
+
+func NewMessage(event *entity.ProductEvent) Message {
+	return Message{
+		ID:         event.ID,
+		Type:       event.Type,
+		ProductID:  event.ProductID,
+		Version:    event.Version,
+		OccurredAt: event.OccurredAt,
+		Data:       event.Data,
+	}
+}
+
+// NewPublisher returns the publisher of the given kind, log or file, along with a function that
+// releases it once no more events are published. path is the file the file publisher appends to.
+func NewPublisher(kind, path string, logger *slog.Logger) (domain.EventPublisher, func() error, error) {
+	switch kind {
+	case "", PublisherLog:
+		return NewLogPublisher(logger), func() error { return nil }, nil
+	case PublisherFile:
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, nil, err
+		}
+		return NewWriterPublisher(file), file.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("invalid event publisher %q, expected log or file", kind)
+	}
+}
+
+// PublisherFunc lets an ordinary function be used as a publisher.
+type PublisherFunc func(ctx context.Context, event *entity.ProductEvent) error
+
+func (f PublisherFunc) Publish(ctx context.Context, event *entity.ProductEvent) error {
+	return f(ctx, event)
+}
+
+// MultiPublisher publishes every event through each of its publishers in turn, stopping at the
+// first one that fails. The event is then published again by all of them.
+type MultiPublisher []domain.EventPublisher
+
+func (m MultiPublisher) Publish(ctx context.Context, event *entity.ProductEvent) error {
+	for _, publisher := range m {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LogPublisher writes every event to the service log. It lets the outbox be inspected while no
+// broker is available.
+type LogPublisher struct {
+	Logger *slog.Logger
+}
+
+func NewLogPublisher(logger *slog.Logger) *LogPublisher {
+	return &LogPublisher{Logger: logger}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, event *entity.ProductEvent) error {
+	p.Logger.InfoContext(ctx, "product event published",
+		"event_id", event.ID, "event_type", event.Type, "product_id", event.ProductID,
+		"version", event.Version, "data", event.Data)
+	return nil
+}
+
+// WriterPublisher writes every event as a line of JSON (NDJSON).
+type WriterPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{w: w}
+}
+
+func (p *WriterPublisher) Publish(ctx context.Context, event *entity.ProductEvent) error {
+	line, err := json.Marshal(NewMessage(event))
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.w.Write(append(line, '\n'))
+	return err
+}
+
+// ChannelPublisher sends every event to a channel, for consumers running in the same process
+// such as tests.
+type ChannelPublisher struct {
+	C chan *entity.ProductEvent
+}
+
+// NewChannelPublisher returns a publisher whose channel buffers up to size events. Once the
+// buffer is full Publish waits for the consumer.
+func NewChannelPublisher(size int) *ChannelPublisher {
+	return &ChannelPublisher{C: make(chan *entity.ProductEvent, size)}
+}
+
+func (p *ChannelPublisher) Publish(ctx context.Context, event *entity.ProductEvent) error {
+	select {
+	case p.C <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,109 @@
+package events_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/events"
+	"github.com/stretchr/testify/require"
+)
+
+var event = &entity.ProductEvent{
+	ID:         "5de5e715-338b-422b-84cd-d5f9d6df8bf3",
+	Type:       entity.PriceChanged,
+	ProductID:  "84a345e0-538f-4f0d-bc71-5c0fdf75b5ef",
+	Version:    3,
+	OccurredAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	Data:       map[string]any{"old_price": 10.0, "new_price": 12.5},
+}
+
+func TestWriterPublisher(t *testing.T) {
+	var buf bytes.Buffer
+	publisher := events.NewWriterPublisher(&buf)
+	require.NoError(t, publisher.Publish(context.Background(), event))
+	require.NoError(t, publisher.Publish(context.Background(), event))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	require.JSONEq(t, `{
+		"id": "5de5e715-338b-422b-84cd-d5f9d6df8bf3",
+		"type": "product.price_changed",
+		"product_id": "84a345e0-538f-4f0d-bc71-5c0fdf75b5ef",
+		"version": 3,
+		"occurred_at": "2024-05-01T12:00:00Z",
+		"data": {"old_price": 10, "new_price": 12.5}
+	}`, lines[0])
+}
+
+func TestLogPublisher(t *testing.T) {
+	var buf bytes.Buffer
+	publisher := events.NewLogPublisher(slog.New(slog.NewJSONHandler(&buf, nil)))
+	require.NoError(t, publisher.Publish(context.Background(), event))
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, "product event published", record["msg"])
+	require.Equal(t, entity.PriceChanged, record["event_type"])
+	require.Equal(t, event.ProductID, record["product_id"])
+}
+
+func TestChannelPublisher(t *testing.T) {
+	publisher := events.NewChannelPublisher(1)
+	require.NoError(t, publisher.Publish(context.Background(), event))
+	require.Equal(t, event, <-publisher.C)
+
+	// Publish waits for the consumer once the buffer is full
+	require.NoError(t, publisher.Publish(context.Background(), event))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, publisher.Publish(ctx, event), context.DeadlineExceeded)
+}
+
+func TestNewPublisher(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	publisher, closePublisher, err := events.NewPublisher("", "", logger)
+	require.NoError(t, err)
+	require.IsType(t, &events.LogPublisher{}, publisher)
+	require.NoError(t, closePublisher())
+
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	publisher, closePublisher, err = events.NewPublisher(events.PublisherFile, path, logger)
+	require.NoError(t, err)
+	require.NoError(t, publisher.Publish(context.Background(), event))
+	require.NoError(t, closePublisher())
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(content), `"type":"product.price_changed"`)
+
+	_, _, err = events.NewPublisher("kafka", "", logger)
+	require.ErrorContains(t, err, "invalid event publisher")
+}
+
+func TestMultiPublisher(t *testing.T) {
+	var published []string
+	record := func(name string, err error) events.PublisherFunc {
+		return func(ctx context.Context, event *entity.ProductEvent) error {
+			published = append(published, name)
+			return err
+		}
+	}
+
+	require.NoError(t, events.MultiPublisher{record("first", nil), record("second", nil)}.Publish(context.Background(), event))
+	require.Equal(t, []string{"first", "second"}, published)
+
+	published = nil
+	err := events.MultiPublisher{record("first", errors.New("unavailable")), record("second", nil)}.Publish(context.Background(), event)
+	require.ErrorContains(t, err, "unavailable")
+	require.Equal(t, []string{"first"}, published)
+}
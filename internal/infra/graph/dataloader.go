@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// loaderWait is how long a loader collects keys before loading them in a single batch.
+const loaderWait = time.Millisecond
+
+type loadersKey struct{}
+
+// Loaders batch and cache the lookups made while resolving a single request. They must not be
+// shared between requests, so that every request sees the products as they are.
+type Loaders struct {
+	Product *dataloader.Loader[string, *usecase.ProductOutputDTO]
+}
+
+func NewLoaders(productRepository domain.ProductRepositoryInterface) *Loaders {
+	return &Loaders{
+		Product: dataloader.NewBatchedLoader(productBatch(productRepository),
+			dataloader.WithWait[string, *usecase.ProductOutputDTO](loaderWait),
+			dataloader.WithBatchCapacity[string, *usecase.ProductOutputDTO](usecase.MaxBatchGetIDs)),
+	}
+}
+
+// WithLoaders returns a copy of ctx carrying loaders.
+func WithLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, loaders)
+}
+
+func loadersFromContext(ctx context.Context) *Loaders {
+	return ctx.Value(loadersKey{}).(*Loaders)
+}
+
+// productBatch loads the products of a batch with a single lookup. Missing products resolve to nil.
+func productBatch(productRepository domain.ProductRepositoryInterface) dataloader.BatchFunc[string, *usecase.ProductOutputDTO] {
+	return func(ctx context.Context, ids []string) []*dataloader.Result[*usecase.ProductOutputDTO] {
+		results := make([]*dataloader.Result[*usecase.ProductOutputDTO], len(ids))
+		// An empty id names no product, and would fail the lookup of the whole batch.
+		lookup := slices.DeleteFunc(slices.Clone(ids), func(id string) bool { return id == "" })
+		found := make(map[string]*usecase.ProductOutputDTO, len(lookup))
+		if len(lookup) > 0 {
+			getProductsByIDsUseCase := usecase.NewGetProductsByIDsUseCase(productRepository)
+			output, err := getProductsByIDsUseCase.Execute(ctx, usecase.ProductBatchGetInputDTO{IDs: lookup})
+			if err != nil {
+				for i := range results {
+					results[i] = &dataloader.Result[*usecase.ProductOutputDTO]{Error: err}
+				}
+				return results
+			}
+			for i := range output.Products {
+				found[output.Products[i].ID] = &output.Products[i]
+			}
+		}
+		for i, id := range ids {
+			// Products carry the canonical form of their id, which the key may be written in another
+			if parsed, err := uuid.Parse(id); err == nil {
+				id = parsed.String()
+			}
+			results[i] = &dataloader.Result[*usecase.ProductOutputDTO]{Data: found[id]}
+		}
+		return results
+	}
+}
@@ -0,0 +1,26 @@
+package graph
+
+import (
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/infra/graph/model"
+)
+
+func newProductFilter(filter *model.ProductFilter) domain.ProductFilter {
+	if filter == nil {
+		return domain.ProductFilter{}
+	}
+	return domain.ProductFilter{
+		Status:     valueOf(filter.Status),
+		MinPrice:   filter.MinPrice,
+		MaxPrice:   filter.MaxPrice,
+		NamePrefix: valueOf(filter.NamePrefix),
+		Query:      valueOf(filter.Query),
+	}
+}
+
+func valueOf(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
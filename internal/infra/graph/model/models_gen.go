@@ -0,0 +1,38 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+import (
+	"github.com/HaroldoFV/product-service/internal/usecase"
+)
+
+type Mutation struct {
+}
+
+// Narrows down a listing. Unset fields do not filter.
+type ProductFilter struct {
+	// enabled or disabled.
+	Status     *string  `json:"status,omitempty"`
+	MinPrice   *float64 `json:"minPrice,omitempty"`
+	MaxPrice   *float64 `json:"maxPrice,omitempty"`
+	NamePrefix *string  `json:"namePrefix,omitempty"`
+	// Searches the name and the description. Every term must match.
+	Query *string `json:"query,omitempty"`
+}
+
+type ProductInput struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Price       float64 `json:"price"`
+}
+
+type ProductPage struct {
+	Products   []*usecase.ProductOutputDTO `json:"products"`
+	TotalCount int                         `json:"totalCount"`
+	Page       int                         `json:"page"`
+	Limit      int                         `json:"limit"`
+	TotalPages int                         `json:"totalPages"`
+}
+
+type Query struct {
+}
@@ -0,0 +1,56 @@
+package graph
+
+//go:generate go run github.com/99designs/gqlgen generate
+
+import (
+	"log/slog"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/infra/graph/model"
+)
+
+// defaultLimit is the page size of products when the query sets limit to null, as in the REST API.
+const defaultLimit = 10
+
+// Resolver holds the dependencies of the resolvers, as WebProductHandler does for the REST API.
+type Resolver struct {
+	ProductRepository  domain.ProductRepositoryInterface
+	AuditRepository    domain.ProductAuditRepositoryInterface
+	OutboxRepository   domain.OutboxRepositoryInterface
+	TransactionManager domain.TransactionManager
+	Logger             *slog.Logger
+}
+
+func NewResolver(
+	productRepository domain.ProductRepositoryInterface,
+	auditRepository domain.ProductAuditRepositoryInterface,
+	outboxRepository domain.OutboxRepositoryInterface,
+	transactionManager domain.TransactionManager,
+	logger *slog.Logger,
+) *Resolver {
+	return &Resolver{
+		ProductRepository:  productRepository,
+		AuditRepository:    auditRepository,
+		OutboxRepository:   outboxRepository,
+		TransactionManager: transactionManager,
+		Logger:             logger,
+	}
+}
+
+// NewSchema returns the executable schema resolved by resolver. The complexity of products is
+// the complexity of a product times limit, so that large pages count against the complexity limit.
+func NewSchema(resolver *Resolver) graphql.ExecutableSchema {
+	config := Config{Resolvers: resolver}
+	config.Complexity.Query.Products = func(childComplexity int, _ *model.ProductFilter, _ *string, _ *int, limit *int) int {
+		return 1 + childComplexity*limitOrDefault(limit)
+	}
+	return NewExecutableSchema(config)
+}
+
+func limitOrDefault(limit *int) int {
+	if limit == nil || *limit < 1 {
+		return defaultLimit
+	}
+	return *limit
+}
@@ -0,0 +1,120 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen
+
+import (
+	"context"
+
+	"github.com/HaroldoFV/product-service/internal/infra/graph/model"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+)
+
+// CreateProduct is the resolver for the createProduct field.
+func (r *mutationResolver) CreateProduct(ctx context.Context, input model.ProductInput) (*usecase.ProductOutputDTO, error) {
+	createProductUseCase := usecase.NewCreateProductUseCase(r.ProductRepository, r.AuditRepository, r.OutboxRepository, r.TransactionManager, r.Logger)
+	output, err := createProductUseCase.Execute(ctx, usecase.ProductInputDTO{
+		Name:        input.Name,
+		Description: input.Description,
+		Price:       input.Price,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// UpdateProduct is the resolver for the updateProduct field.
+func (r *mutationResolver) UpdateProduct(ctx context.Context, id string, input model.ProductInput, expectedVersion *int) (*usecase.ProductOutputDTO, error) {
+	dto := usecase.ProductUpdateInputDTO{
+		ID:          id,
+		Name:        input.Name,
+		Description: input.Description,
+		Price:       input.Price,
+	}
+	if expectedVersion != nil {
+		dto.IfMatch = []int{*expectedVersion}
+	}
+
+	updateProductUseCase := usecase.NewUpdateProductUseCase(r.ProductRepository, r.AuditRepository, r.OutboxRepository, r.TransactionManager, r.Logger)
+	output, err := updateProductUseCase.Execute(ctx, dto)
+	if err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// DeleteProduct is the resolver for the deleteProduct field.
+func (r *mutationResolver) DeleteProduct(ctx context.Context, id string, permanent *bool) (bool, error) {
+	deleteProductUseCase := usecase.NewDeleteProductUseCase(r.ProductRepository, r.AuditRepository, r.OutboxRepository, r.TransactionManager, r.Logger)
+	err := deleteProductUseCase.Execute(ctx, id, permanent != nil && *permanent)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// EnableProduct is the resolver for the enableProduct field.
+func (r *mutationResolver) EnableProduct(ctx context.Context, id string) (*usecase.ProductOutputDTO, error) {
+	enableProductUseCase := usecase.NewEnableProductUseCase(r.ProductRepository, r.AuditRepository, r.OutboxRepository, r.TransactionManager, r.Logger)
+	output, err := enableProductUseCase.Execute(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// DisableProduct is the resolver for the disableProduct field.
+func (r *mutationResolver) DisableProduct(ctx context.Context, id string) (*usecase.ProductOutputDTO, error) {
+	disableProductUseCase := usecase.NewDisableProductUseCase(r.ProductRepository, r.AuditRepository, r.OutboxRepository, r.TransactionManager, r.Logger)
+	output, err := disableProductUseCase.Execute(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// Product is the resolver for the product field.
+func (r *queryResolver) Product(ctx context.Context, id string) (*usecase.ProductOutputDTO, error) {
+	return loadersFromContext(ctx).Product.Load(ctx, id)()
+}
+
+// Products is the resolver for the products field.
+func (r *queryResolver) Products(ctx context.Context, filter *model.ProductFilter, sort *string, page *int, limit *int) (*model.ProductPage, error) {
+	pageNumber, pageLimit := 1, limitOrDefault(limit)
+	if page != nil && *page > 1 {
+		pageNumber = *page
+	}
+	productSort := "id"
+	if sort != nil && *sort != "" {
+		productSort = *sort
+	}
+
+	listProductsUseCase := usecase.NewListProductsUseCase(r.ProductRepository)
+	output, totalCount, err := listProductsUseCase.Execute(ctx, newProductFilter(filter), pageNumber, pageLimit, productSort)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.ProductPage{
+		Products:   make([]*usecase.ProductOutputDTO, len(output)),
+		TotalCount: totalCount,
+		Page:       pageNumber,
+		Limit:      pageLimit,
+		TotalPages: (totalCount + pageLimit - 1) / pageLimit,
+	}
+	for i := range output {
+		result.Products[i] = &output[i]
+	}
+	return result, nil
+}
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Output formats accepted by NewLogger.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// RedactedValue replaces the value of sensitive attributes.
+const RedactedValue = "[REDACTED]"
+
+// Attributes added by ContextHandler to the log lines written while serving a request.
+const (
+	RequestIDKey = "request_id"
+	TraceIDKey   = "trace_id"
+	SpanIDKey    = "span_id"
+)
+
+// sensitiveKeys are substrings of attribute keys whose values are never written to the logs.
+var sensitiveKeys = []string{"password", "secret", "token", "authorization", "cookie"}
+
+type requestIDContextKey struct{}
+
+type loggerContextKey struct{}
+
+// NewLogger returns a logger writing to w in the given format, json or text, discarding records
+// below level (debug, info, warn or error). Empty values mean json and info. The values of
+// sensitive attributes are redacted and the request ID and trace found in the context, if any,
+// are added to every record logged with the *Context methods.
+func NewLogger(w io.Writer, format, level string) (*slog.Logger, error) {
+	var logLevel slog.Level
+	if level != "" {
+		if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+			return nil, fmt.Errorf("invalid log level %q, expected debug, info, warn or error", level)
+		}
+	}
+
+	options := &slog.HandlerOptions{Level: logLevel, ReplaceAttr: redact}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "", FormatJSON:
+		handler = slog.NewJSONHandler(w, options)
+	case FormatText:
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return nil, fmt.Errorf("invalid log format %q, expected json or text", format)
+	}
+	return slog.New(ContextHandler{Handler: handler}), nil
+}
+
+// IsSensitive reports whether the value of an attribute named key must be redacted.
+func IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() != slog.KindGroup && IsSensitive(a.Key) {
+		return slog.String(a.Key, RedactedValue)
+	}
+	return a
+}
+
+// ContextHandler adds the request ID and the current span stored in the context to every
+// record, so that log lines can be joined with traces.
+type ContextHandler struct {
+	slog.Handler
+}
+
+func (h ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		record.AddAttrs(slog.String(RequestIDKey, requestID))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(
+			slog.String(TraceIDKey, spanContext.TraceID().String()),
+			slog.String(SpanIDKey, spanContext.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return ContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h ContextHandler) WithGroup(name string) slog.Handler {
+	return ContextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request being served.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored by WithRequestID, or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// NewContext returns a copy of ctx carrying logger, for code that has no logger of its own such
+// as the helpers writing HTTP responses.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// FromContext returns the logger stored by NewContext, or slog.Default when there is none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerContextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
@@ -0,0 +1,105 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/infra/logging"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewLogger(t *testing.T) {
+	t.Run("JSON output with request ID", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := logging.NewLogger(&buf, "json", "info")
+		require.NoError(t, err)
+
+		ctx := logging.WithRequestID(context.Background(), "abc-123")
+		logger.InfoContext(ctx, "product created", "product_id", "42")
+
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		require.Equal(t, "product created", record["msg"])
+		require.Equal(t, "INFO", record["level"])
+		require.Equal(t, "42", record["product_id"])
+		require.Equal(t, "abc-123", record[logging.RequestIDKey])
+	})
+
+	t.Run("Trace IDs", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := logging.NewLogger(&buf, "json", "info")
+		require.NoError(t, err)
+
+		traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+		spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID,
+			SpanID:  spanID,
+		}))
+		logger.InfoContext(ctx, "traced")
+
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record[logging.TraceIDKey])
+		require.Equal(t, "00f067aa0ba902b7", record[logging.SpanIDKey])
+	})
+
+	t.Run("Text output", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := logging.NewLogger(&buf, "text", "")
+		require.NoError(t, err)
+
+		logger.With("component", "test").InfoContext(logging.WithRequestID(context.Background(), "abc-123"), "hello")
+		require.Contains(t, buf.String(), "msg=hello")
+		require.Contains(t, buf.String(), "component=test")
+		require.Contains(t, buf.String(), "request_id=abc-123")
+	})
+
+	t.Run("Level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := logging.NewLogger(&buf, "", "warn")
+		require.NoError(t, err)
+
+		logger.Info("ignored")
+		require.Empty(t, buf.String())
+		logger.Warn("kept")
+		require.Contains(t, buf.String(), "kept")
+	})
+
+	t.Run("Redacts sensitive attributes", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := logging.NewLogger(&buf, "json", "info")
+		require.NoError(t, err)
+
+		logger.Info("config",
+			"db_password", "hunter2",
+			slog.Group("config", "cursor_secret", "s3cr3t", "Authorization", "Bearer xyz", "db_user", "root"),
+		)
+		require.NotContains(t, buf.String(), "hunter2")
+		require.NotContains(t, buf.String(), "s3cr3t")
+		require.NotContains(t, buf.String(), "Bearer xyz")
+		require.Contains(t, buf.String(), `"db_user":"root"`)
+		require.Contains(t, buf.String(), `"db_password":"[REDACTED]"`)
+	})
+
+	t.Run("Invalid format", func(t *testing.T) {
+		_, err := logging.NewLogger(&bytes.Buffer{}, "xml", "info")
+		require.ErrorContains(t, err, "invalid log format")
+	})
+
+	t.Run("Invalid level", func(t *testing.T) {
+		_, err := logging.NewLogger(&bytes.Buffer{}, "json", "verbose")
+		require.ErrorContains(t, err, "invalid log level")
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	require.Equal(t, slog.Default(), logging.FromContext(context.Background()))
+
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	require.Equal(t, logger, logging.FromContext(logging.NewContext(context.Background(), logger)))
+}
@@ -0,0 +1,69 @@
+package metrics_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/infra/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	httpMetrics := metrics.NewHTTPMetrics(registry)
+
+	handler := httpMetrics.Instrument("/api/v1/products/{id}", http.MethodGet, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/products/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+
+	for _, path := range []string{"/api/v1/products/1", "/api/v1/products/2", "/api/v1/products/missing"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	expected := `
+# HELP http_requests_total Number of HTTP requests served, by route pattern, method and status code.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",route="/api/v1/products/{id}",status="200"} 2
+http_requests_total{method="GET",route="/api/v1/products/{id}",status="404"} 1
+# HELP http_requests_in_flight Number of HTTP requests being served, by route pattern and method.
+# TYPE http_requests_in_flight gauge
+http_requests_in_flight{method="GET",route="/api/v1/products/{id}"} 0
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "http_requests_total", "http_requests_in_flight"))
+}
+
+func TestInstrumentedProductRepository(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	repository := metrics.NewInstrumentedProductRepository(database.NewInMemoryProductRepository(), registry)
+	ctx := context.Background()
+
+	product, err := entity.NewProduct("Product", "Description", 10)
+	require.NoError(t, err)
+	require.NoError(t, repository.Create(ctx, product))
+	require.Error(t, repository.Create(ctx, product))
+	_, err = repository.GetByID(ctx, "missing")
+	require.ErrorIs(t, err, entity.ErrNotFound)
+	_, _, err = repository.List(ctx, domain.ProductFilter{}, 1, 10, domain.ProductSort{{Field: domain.SortByID}})
+	require.NoError(t, err)
+
+	expected := `
+# HELP product_repository_errors_total Number of product repository calls that returned an error, by method and kind of error.
+# TYPE product_repository_errors_total counter
+product_repository_errors_total{kind="conflict",method="Create"} 1
+product_repository_errors_total{kind="not_found",method="GetByID"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "product_repository_errors_total"))
+	require.Equal(t, 3, testutil.CollectAndCount(registry, "product_repository_duration_seconds"))
+}
@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Kinds of errors counted by InstrumentedProductRepository. Domain outcomes such as a missing
+// product are told apart from failures so that alerts can target the latter.
+const (
+	errorKindNotFound     = "not_found"
+	errorKindConflict     = "conflict"
+	errorKindInvalidInput = "invalid_input"
+	errorKindTimeout      = "timeout"
+	errorKindCanceled     = "canceled"
+	errorKindInternal     = "internal"
+)
+
+// InstrumentedProductRepository decorates a ProductRepositoryInterface, recording the latency
+// and errors of every call.
+type InstrumentedProductRepository struct {
+	ProductRepository domain.ProductRepositoryInterface
+	duration          *prometheus.HistogramVec
+	errors            *prometheus.CounterVec
+}
+
+func NewInstrumentedProductRepository(
+	productRepository domain.ProductRepositoryInterface,
+	registerer prometheus.Registerer,
+) *InstrumentedProductRepository {
+	r := &InstrumentedProductRepository{
+		ProductRepository: productRepository,
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "product_repository_duration_seconds",
+			Help:    "Time taken by product repository calls, by method.",
+			Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{"method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "product_repository_errors_total",
+			Help: "Number of product repository calls that returned an error, by method and kind of error.",
+		}, []string{"method", "kind"}),
+	}
+	registerer.MustRegister(r.duration, r.errors)
+	return r
+}
+
+func (r *InstrumentedProductRepository) Create(ctx context.Context, product *entity.Product) error {
+	defer r.observe("Create", time.Now())
+	return r.count("Create", r.ProductRepository.Create(ctx, product))
+}
+
+func (r *InstrumentedProductRepository) CreateBatch(ctx context.Context, products []*entity.Product) error {
+	defer r.observe("CreateBatch", time.Now())
+	return r.count("CreateBatch", r.ProductRepository.CreateBatch(ctx, products))
+}
+
+func (r *InstrumentedProductRepository) Update(ctx context.Context, product *entity.Product) error {
+	defer r.observe("Update", time.Now())
+	return r.count("Update", r.ProductRepository.Update(ctx, product))
+}
+
+func (r *InstrumentedProductRepository) GetByID(ctx context.Context, id string) (*entity.Product, error) {
+	defer r.observe("GetByID", time.Now())
+	product, err := r.ProductRepository.GetByID(ctx, id)
+	return product, r.count("GetByID", err)
+}
+
+func (r *InstrumentedProductRepository) GetByIDs(ctx context.Context, ids []string) ([]*entity.Product, error) {
+	defer r.observe("GetByIDs", time.Now())
+	products, err := r.ProductRepository.GetByIDs(ctx, ids)
+	return products, r.count("GetByIDs", err)
+}
+
+func (r *InstrumentedProductRepository) List(ctx context.Context, filter domain.ProductFilter, page, limit int, sort domain.ProductSort) ([]*entity.Product, int, error) {
+	defer r.observe("List", time.Now())
+	products, total, err := r.ProductRepository.List(ctx, filter, page, limit, sort)
+	return products, total, r.count("List", err)
+}
+
+func (r *InstrumentedProductRepository) ListByCursor(ctx context.Context, filter domain.ProductFilter, sort domain.ProductSort, cursor *domain.ProductCursor, limit int) ([]*entity.Product, error) {
+	defer r.observe("ListByCursor", time.Now())
+	products, err := r.ProductRepository.ListByCursor(ctx, filter, sort, cursor, limit)
+	return products, r.count("ListByCursor", err)
+}
+
+// ForEach records the duration of the whole iteration, including the time spent in fn.
+func (r *InstrumentedProductRepository) ForEach(ctx context.Context, filter domain.ProductFilter, fn func(*entity.Product) error) error {
+	defer r.observe("ForEach", time.Now())
+	return r.count("ForEach", r.ProductRepository.ForEach(ctx, filter, fn))
+}
+
+func (r *InstrumentedProductRepository) Delete(ctx context.Context, id string) (*entity.Product, error) {
+	defer r.observe("Delete", time.Now())
+	product, err := r.ProductRepository.Delete(ctx, id)
+	return product, r.count("Delete", err)
+}
+
+func (r *InstrumentedProductRepository) Restore(ctx context.Context, id string) (*entity.Product, error) {
+	defer r.observe("Restore", time.Now())
+	product, err := r.ProductRepository.Restore(ctx, id)
+	return product, r.count("Restore", err)
+}
+
+func (r *InstrumentedProductRepository) ListDeleted(ctx context.Context, page, limit int) ([]*entity.Product, int, error) {
+	defer r.observe("ListDeleted", time.Now())
+	products, total, err := r.ProductRepository.ListDeleted(ctx, page, limit)
+	return products, total, r.count("ListDeleted", err)
+}
+
+func (r *InstrumentedProductRepository) DeletePermanently(ctx context.Context, id string) (*entity.Product, error) {
+	defer r.observe("DeletePermanently", time.Now())
+	product, err := r.ProductRepository.DeletePermanently(ctx, id)
+	return product, r.count("DeletePermanently", err)
+}
+
+func (r *InstrumentedProductRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) ([]*entity.Product, error) {
+	defer r.observe("PurgeDeleted", time.Now())
+	purged, err := r.ProductRepository.PurgeDeleted(ctx, deletedBefore)
+	return purged, r.count("PurgeDeleted", err)
+}
+
+func (r *InstrumentedProductRepository) observe(method string, start time.Time) {
+	r.duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+// count records err, if any, and returns it unchanged.
+func (r *InstrumentedProductRepository) count(method string, err error) error {
+	if err != nil {
+		r.errors.WithLabelValues(method, errorKind(err)).Inc()
+	}
+	return err
+}
+
+func errorKind(err error) string {
+	switch {
+	case errors.Is(err, entity.ErrNotFound):
+		return errorKindNotFound
+	case errors.Is(err, entity.ErrConflict):
+		return errorKindConflict
+	case errors.Is(err, entity.ErrInvalidInput):
+		return errorKindInvalidInput
+	case errors.Is(err, context.DeadlineExceeded):
+		return errorKindTimeout
+	case errors.Is(err, context.Canceled):
+		return errorKindCanceled
+	default:
+		return errorKindInternal
+	}
+}
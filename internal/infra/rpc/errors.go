@@ -0,0 +1,96 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/logging"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// codeFromError maps domain errors to the status code returned to the client, as
+// statusCodeFromError does for HTTP. Anything not recognized as a domain error is internal.
+func codeFromError(err error) codes.Code {
+	switch {
+	case errors.Is(err, entity.ErrInvalidInput), errors.Is(err, entity.ErrValidation):
+		return codes.InvalidArgument
+	case errors.Is(err, entity.ErrNotFound):
+		return codes.NotFound
+	case errors.Is(err, entity.ErrConflict):
+		return codes.Aborted
+	case errors.Is(err, entity.ErrPreconditionFailed):
+		return codes.FailedPrecondition
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	default:
+		return codes.Internal
+	}
+}
+
+// statusFromError converts err to a status error. Details of internal errors are not exposed to
+// the client, and validation errors carry a BadRequest detail listing the invalid fields. Errors
+// caused by ctx ending, which database drivers do not always wrap, are reported as such.
+func statusFromError(ctx context.Context, err error) error {
+	if _, ok := status.FromError(err); ok {
+		// Already a status, such as the error of a failed Send
+		return err
+	}
+
+	code := codeFromError(err)
+	if ctxErr := ctx.Err(); code == codes.Internal && ctxErr != nil {
+		code = codeFromError(ctxErr)
+	}
+
+	message := err.Error()
+	switch code {
+	case codes.Internal:
+		logging.FromContext(ctx).ErrorContext(ctx, "internal error", "error", err)
+		message = "an unexpected error occurred"
+	case codes.DeadlineExceeded:
+		message = "the request took too long to complete"
+	case codes.Canceled:
+		message = "the client canceled the request"
+	}
+
+	st := status.New(code, message)
+	if violations := fieldViolations(err); len(violations) > 0 {
+		if detailed, detailErr := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); detailErr == nil {
+			st = detailed
+		}
+	}
+	return st.Err()
+}
+
+// fieldViolations collects every ValidationError wrapped in err, including errors joined with
+// errors.Join.
+func fieldViolations(err error) []*errdetails.BadRequest_FieldViolation {
+	var result []*errdetails.BadRequest_FieldViolation
+	var walk func(error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		if validationErr, ok := err.(*entity.ValidationError); ok {
+			result = append(result, &errdetails.BadRequest_FieldViolation{
+				Field:       validationErr.Field,
+				Description: validationErr.Message,
+			})
+			return
+		}
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				walk(inner)
+			}
+		case interface{ Unwrap() error }:
+			walk(e.Unwrap())
+		}
+	}
+	walk(err)
+	return result
+}
@@ -0,0 +1,225 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/rpc/productv1"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultLimit is the page size of List when the request does not set one, as in the REST API.
+const defaultLimit = 10
+
+// ProductServer implements productv1.ProductServiceServer with the same use cases as the REST
+// handlers.
+type ProductServer struct {
+	productv1.UnimplementedProductServiceServer
+
+	ProductRepository  domain.ProductRepositoryInterface
+	AuditRepository    domain.ProductAuditRepositoryInterface
+	OutboxRepository   domain.OutboxRepositoryInterface
+	TransactionManager domain.TransactionManager
+	Logger             *slog.Logger
+}
+
+func NewProductServer(
+	productRepository domain.ProductRepositoryInterface,
+	auditRepository domain.ProductAuditRepositoryInterface,
+	outboxRepository domain.OutboxRepositoryInterface,
+	transactionManager domain.TransactionManager,
+	logger *slog.Logger,
+) *ProductServer {
+	return &ProductServer{
+		ProductRepository:  productRepository,
+		AuditRepository:    auditRepository,
+		OutboxRepository:   outboxRepository,
+		TransactionManager: transactionManager,
+		Logger:             logger,
+	}
+}
+
+func (s *ProductServer) Create(ctx context.Context, request *productv1.CreateProductRequest) (*productv1.Product, error) {
+	createProductUseCase := usecase.NewCreateProductUseCase(s.ProductRepository, s.AuditRepository, s.OutboxRepository, s.TransactionManager, s.Logger)
+	output, err := createProductUseCase.Execute(ctx, usecase.ProductInputDTO{
+		Name:        request.GetName(),
+		Description: request.GetDescription(),
+		Price:       request.GetPrice(),
+	})
+	if err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+	return newProduct(output), nil
+}
+
+func (s *ProductServer) Get(ctx context.Context, request *productv1.GetProductRequest) (*productv1.Product, error) {
+	if request.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing product ID")
+	}
+
+	getProductUseCase := usecase.NewGetProductUseCase(s.ProductRepository)
+	output, err := getProductUseCase.Execute(ctx, request.GetId())
+	if err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+	return newProduct(output), nil
+}
+
+func (s *ProductServer) List(ctx context.Context, request *productv1.ListProductsRequest) (*productv1.ListProductsResponse, error) {
+	page, limit := int(request.GetPage()), int(request.GetLimit())
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	sort := request.GetSort()
+	if sort == "" {
+		sort = "id"
+	}
+
+	listProductsUseCase := usecase.NewListProductsUseCase(s.ProductRepository)
+	output, totalCount, err := listProductsUseCase.Execute(ctx, newProductFilter(request.GetFilter()), page, limit, sort)
+	if err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+
+	response := &productv1.ListProductsResponse{
+		Products:   make([]*productv1.Product, len(output)),
+		TotalCount: int32(totalCount),
+		Page:       int32(page),
+		Limit:      int32(limit),
+		TotalPages: int32((totalCount + limit - 1) / limit),
+	}
+	for i, product := range output {
+		response.Products[i] = newProduct(product)
+	}
+	return response, nil
+}
+
+func (s *ProductServer) Update(ctx context.Context, request *productv1.UpdateProductRequest) (*productv1.Product, error) {
+	if request.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing product ID")
+	}
+
+	input := usecase.ProductUpdateInputDTO{
+		ID:          request.GetId(),
+		Name:        request.GetName(),
+		Description: request.GetDescription(),
+		Price:       request.GetPrice(),
+	}
+	if request.ExpectedVersion != nil {
+		input.IfMatch = []int{int(request.GetExpectedVersion())}
+	}
+
+	updateProductUseCase := usecase.NewUpdateProductUseCase(s.ProductRepository, s.AuditRepository, s.OutboxRepository, s.TransactionManager, s.Logger)
+	output, err := updateProductUseCase.Execute(ctx, input)
+	if err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+	return newProduct(output), nil
+}
+
+func (s *ProductServer) Delete(ctx context.Context, request *productv1.DeleteProductRequest) (*productv1.DeleteProductResponse, error) {
+	if request.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing product ID")
+	}
+
+	deleteProductUseCase := usecase.NewDeleteProductUseCase(s.ProductRepository, s.AuditRepository, s.OutboxRepository, s.TransactionManager, s.Logger)
+	err := deleteProductUseCase.Execute(ctx, request.GetId(), request.GetPermanent())
+	if err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+	return &productv1.DeleteProductResponse{}, nil
+}
+
+func (s *ProductServer) Enable(ctx context.Context, request *productv1.EnableProductRequest) (*productv1.Product, error) {
+	if request.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing product ID")
+	}
+
+	enableProductUseCase := usecase.NewEnableProductUseCase(s.ProductRepository, s.AuditRepository, s.OutboxRepository, s.TransactionManager, s.Logger)
+	output, err := enableProductUseCase.Execute(ctx, request.GetId())
+	if err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+	return newProduct(output), nil
+}
+
+func (s *ProductServer) Disable(ctx context.Context, request *productv1.DisableProductRequest) (*productv1.Product, error) {
+	if request.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing product ID")
+	}
+
+	disableProductUseCase := usecase.NewDisableProductUseCase(s.ProductRepository, s.AuditRepository, s.OutboxRepository, s.TransactionManager, s.Logger)
+	output, err := disableProductUseCase.Execute(ctx, request.GetId())
+	if err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+	return newProduct(output), nil
+}
+
+func (s *ProductServer) ListAll(request *productv1.ListAllProductsRequest, stream grpc.ServerStreamingServer[productv1.Product]) error {
+	ctx := stream.Context()
+	listAllProductsUseCase := usecase.NewListAllProductsUseCase(s.ProductRepository)
+	err := listAllProductsUseCase.Execute(ctx, newProductFilter(request.GetFilter()), func(product usecase.ProductOutputDTO) error {
+		return stream.Send(newProduct(product))
+	})
+	if err != nil {
+		return statusFromError(ctx, err)
+	}
+	return nil
+}
+
+func newProduct(output usecase.ProductOutputDTO) *productv1.Product {
+	return &productv1.Product{
+		Id:          output.ID,
+		Name:        output.Name,
+		Description: output.Description,
+		Price:       output.Price,
+		Status:      newProductStatus(output.Status),
+		CreatedAt:   timestamppb.New(output.CreatedAt),
+		Version:     int32(output.Version),
+	}
+}
+
+func newProductStatus(productStatus string) productv1.ProductStatus {
+	switch productStatus {
+	case entity.ENABLED:
+		return productv1.ProductStatus_PRODUCT_STATUS_ENABLED
+	case entity.DISABLED:
+		return productv1.ProductStatus_PRODUCT_STATUS_DISABLED
+	default:
+		return productv1.ProductStatus_PRODUCT_STATUS_UNSPECIFIED
+	}
+}
+
+// newProductFilter converts filter, which may be nil, to a domain filter. Unknown statuses are kept
+// so that the use cases reject them.
+func newProductFilter(filter *productv1.ProductFilter) domain.ProductFilter {
+	if filter == nil {
+		return domain.ProductFilter{}
+	}
+	result := domain.ProductFilter{
+		NamePrefix: filter.GetNamePrefix(),
+		Query:      filter.GetQuery(),
+		MinPrice:   filter.MinPrice,
+		MaxPrice:   filter.MaxPrice,
+	}
+	switch filter.GetStatus() {
+	case productv1.ProductStatus_PRODUCT_STATUS_ENABLED:
+		result.Status = entity.ENABLED
+	case productv1.ProductStatus_PRODUCT_STATUS_DISABLED:
+		result.Status = entity.DISABLED
+	case productv1.ProductStatus_PRODUCT_STATUS_UNSPECIFIED:
+	default:
+		result.Status = filter.GetStatus().String()
+	}
+	return result
+}
@@ -0,0 +1,181 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/infra/rpc"
+	"github.com/HaroldoFV/product-service/internal/infra/rpc/productv1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+type testServer struct {
+	server          *rpc.Server
+	conn            *grpc.ClientConn
+	client          productv1.ProductServiceClient
+	auditRepository domain.ProductAuditRepositoryInterface
+}
+
+// startServer serves a ProductServer backed by in-memory repositories over an in-process listener.
+func startServer(t *testing.T) *testServer {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	auditRepository := database.NewInMemoryProductAuditRepository()
+	productServer := rpc.NewProductServer(database.NewInMemoryProductRepository(), auditRepository,
+		database.NewInMemoryProductOutboxRepository(), database.NewInMemoryTransactionManager(), logger)
+	server := rpc.NewServer("", productServer, logger)
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return &testServer{server: server, conn: conn, client: productv1.NewProductServiceClient(conn), auditRepository: auditRepository}
+}
+
+func TestProductServer(t *testing.T) {
+	s := startServer(t)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), rpc.ActorMetadata, "alice")
+
+	var header metadata.MD
+	created, err := s.client.Create(ctx, &productv1.CreateProductRequest{Name: "Product", Description: "description", Price: 10}, grpc.Header(&header))
+	require.NoError(t, err)
+	require.NotEmpty(t, created.GetId())
+	require.Equal(t, productv1.ProductStatus_PRODUCT_STATUS_DISABLED, created.GetStatus())
+	require.Equal(t, int32(1), created.GetVersion())
+	require.NotEmpty(t, header.Get(rpc.RequestIDMetadata), "the request ID is echoed back")
+
+	found, err := s.client.Get(ctx, &productv1.GetProductRequest{Id: created.GetId()})
+	require.NoError(t, err)
+	require.True(t, proto.Equal(created, found))
+
+	enabled, err := s.client.Enable(ctx, &productv1.EnableProductRequest{Id: created.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, productv1.ProductStatus_PRODUCT_STATUS_ENABLED, enabled.GetStatus())
+
+	updated, err := s.client.Update(ctx, &productv1.UpdateProductRequest{
+		Id: created.GetId(), Name: "Renamed", Description: "description", Price: 12, ExpectedVersion: proto.Int32(enabled.GetVersion()),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Renamed", updated.GetName())
+
+	// The actor of the metadata is recorded in the audit trail
+	entries, _, err := s.auditRepository.ListByProduct(context.Background(), created.GetId(), 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, "alice", entries[0].Actor)
+
+	disabled, err := s.client.Disable(ctx, &productv1.DisableProductRequest{Id: created.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, productv1.ProductStatus_PRODUCT_STATUS_DISABLED, disabled.GetStatus())
+
+	list, err := s.client.List(ctx, &productv1.ListProductsRequest{
+		Filter: &productv1.ProductFilter{Status: productv1.ProductStatus_PRODUCT_STATUS_DISABLED, MinPrice: proto.Float64(11)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), list.GetTotalCount())
+	require.Equal(t, int32(10), list.GetLimit(), "default limit")
+	require.Equal(t, created.GetId(), list.GetProducts()[0].GetId())
+
+	_, err = s.client.Delete(ctx, &productv1.DeleteProductRequest{Id: created.GetId()})
+	require.NoError(t, err)
+	_, err = s.client.Get(ctx, &productv1.GetProductRequest{Id: created.GetId()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestProductServerErrors(t *testing.T) {
+	s := startServer(t)
+	ctx := context.Background()
+
+	_, err := s.client.Create(ctx, &productv1.CreateProductRequest{Name: "", Price: -1})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	var badRequest *errdetails.BadRequest
+	for _, detail := range status.Convert(err).Details() {
+		if d, ok := detail.(*errdetails.BadRequest); ok {
+			badRequest = d
+		}
+	}
+	require.NotNil(t, badRequest, "validation errors list the invalid fields")
+	require.NotEmpty(t, badRequest.GetFieldViolations())
+
+	_, err = s.client.Get(ctx, &productv1.GetProductRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.client.Enable(ctx, &productv1.EnableProductRequest{Id: "8f8e7d36-1a2b-4c3d-9e8f-0a1b2c3d4e5f"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = s.client.List(ctx, &productv1.ListProductsRequest{Sort: "weight"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.client.List(ctx, &productv1.ListProductsRequest{Filter: &productv1.ProductFilter{Status: 7}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	created, err := s.client.Create(ctx, &productv1.CreateProductRequest{Name: "Product", Price: 10})
+	require.NoError(t, err)
+	_, err = s.client.Update(ctx, &productv1.UpdateProductRequest{Id: created.GetId(), Name: "Renamed", Price: 10, ExpectedVersion: proto.Int32(5)})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestProductServerListAll(t *testing.T) {
+	s := startServer(t)
+	ctx := context.Background()
+
+	ids := make(map[string]bool)
+	for _, name := range []string{"Keyboard", "Mouse", "Monitor"} {
+		created, err := s.client.Create(ctx, &productv1.CreateProductRequest{Name: name, Price: 10})
+		require.NoError(t, err)
+		ids[created.GetId()] = true
+	}
+
+	stream, err := s.client.ListAll(ctx, &productv1.ListAllProductsRequest{Filter: &productv1.ProductFilter{NamePrefix: "M"}})
+	require.NoError(t, err)
+	var received []*productv1.Product
+	for {
+		product, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		received = append(received, product)
+	}
+	require.Len(t, received, 2)
+	require.Less(t, received[0].GetId(), received[1].GetId(), "ordered by id")
+	for _, product := range received {
+		require.True(t, ids[product.GetId()])
+	}
+
+	stream, err = s.client.ListAll(ctx, &productv1.ListAllProductsRequest{Filter: &productv1.ProductFilter{MinPrice: proto.Float64(-1)}})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestHealth(t *testing.T) {
+	s := startServer(t)
+	client := healthpb.NewHealthClient(s.conn)
+
+	response, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "product.v1.ProductService"})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, response.GetStatus())
+
+	s.server.SetShuttingDown()
+	response, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, response.GetStatus())
+}
@@ -0,0 +1,181 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"regexp"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/infra/logging"
+	"github.com/HaroldoFV/product-service/internal/infra/rpc/productv1"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys read from every call, the gRPC counterparts of the X-Request-ID and X-Actor
+// headers of the REST API. The request ID is echoed back in the response header.
+const (
+	RequestIDMetadata = "x-request-id"
+	ActorMetadata     = "x-actor"
+)
+
+// validRequestID and validActor apply the rules of the REST API to the metadata.
+var (
+	validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:/+=-]{1,128}$`)
+	validActor     = regexp.MustCompile(`^[A-Za-z0-9._:/+=@-]{1,128}$`)
+)
+
+// Server serves ProductService along with the standard health and reflection services.
+type Server struct {
+	Port   string
+	Logger *slog.Logger
+
+	server *grpc.Server
+	health *health.Server
+}
+
+func NewServer(port string, productServer productv1.ProductServiceServer, logger *slog.Logger) *Server {
+	server := grpc.NewServer(
+		// Calls are traced with the global tracer provider, continuing the trace of the caller
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unaryInterceptor(logger)),
+		grpc.ChainStreamInterceptor(streamInterceptor(logger)),
+	)
+	productv1.RegisterProductServiceServer(server, productServer)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(productv1.ProductService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	return &Server{
+		Port:   port,
+		Logger: logger,
+		server: server,
+		health: healthServer,
+	}
+}
+
+// Start listens on Port and serves calls until Shutdown is called, returning nil in that case.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.Port)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve serves calls on listener until Shutdown is called, returning nil in that case.
+func (s *Server) Serve(listener net.Listener) error {
+	return s.server.Serve(listener)
+}
+
+// SetShuttingDown makes the health service report every service as not serving, as /readyz does.
+func (s *Server) SetShuttingDown() {
+	s.health.Shutdown()
+}
+
+// Shutdown stops accepting calls and waits for the in-flight ones to finish. Once ctx is done the
+// remaining calls are canceled and ctx.Err() is returned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		<-stopped
+		return ctx.Err()
+	}
+}
+
+// callContext returns ctx with the request ID, the logger and the actor of the call, along with
+// the request ID to echo back.
+func callContext(ctx context.Context, logger *slog.Logger) (context.Context, string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	id := firstValue(md, RequestIDMetadata)
+	if !validRequestID.MatchString(id) {
+		id = uuid.NewString()
+	}
+	ctx = logging.WithRequestID(ctx, id)
+	ctx = logging.NewContext(ctx, logger)
+
+	name := firstValue(md, ActorMetadata)
+	if !validActor.MatchString(name) {
+		if name != "" {
+			logger.WarnContext(ctx, "ignoring invalid actor metadata", "metadata", ActorMetadata)
+		}
+		name = domain.AnonymousActor
+	}
+	return domain.WithActor(ctx, name), id
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// logCall logs a call once it has been served. Server errors are logged at the error level,
+// everything else at info.
+func logCall(ctx context.Context, logger *slog.Logger, method string, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable, codes.Unimplemented:
+		level = slog.LevelError
+	}
+	logger.LogAttrs(ctx, level, "rpc served",
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+	)
+}
+
+func unaryInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx, id := callContext(ctx, logger)
+		grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadata, id))
+
+		response, err := handler(ctx, request)
+		logCall(ctx, logger, info.FullMethod, start, err)
+		return response, err
+	}
+}
+
+func streamInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, id := callContext(stream.Context(), logger)
+		stream.SetHeader(metadata.Pairs(RequestIDMetadata, id))
+
+		err := handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+		logCall(ctx, logger, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// contextStream replaces the context of a stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
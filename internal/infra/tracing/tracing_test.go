@@ -0,0 +1,46 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/infra/tracing"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup(t *testing.T) {
+	t.Run("Stdout exporter", func(t *testing.T) {
+		var buf bytes.Buffer
+		shutdown, err := tracing.Setup(context.Background(), tracing.Config{
+			Exporter:    tracing.ExporterStdout,
+			ServiceName: "product-service-test",
+			SampleRatio: 1,
+			Stdout:      &buf,
+		})
+		require.NoError(t, err)
+
+		_, span := otel.Tracer("test").Start(context.Background(), "test span")
+		span.End()
+		require.NoError(t, shutdown(context.Background()))
+
+		require.Contains(t, buf.String(), `"Name":"test span"`)
+		require.Contains(t, buf.String(), "product-service-test")
+	})
+
+	t.Run("No exporter", func(t *testing.T) {
+		shutdown, err := tracing.Setup(context.Background(), tracing.Config{Exporter: tracing.ExporterNone})
+		require.NoError(t, err)
+
+		_, span := otel.Tracer("test").Start(context.Background(), "test span")
+		require.False(t, span.SpanContext().IsValid())
+		span.End()
+		require.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("Invalid exporter", func(t *testing.T) {
+		_, err := tracing.Setup(context.Background(), tracing.Config{Exporter: "zipkin"})
+		require.ErrorContains(t, err, "invalid tracing exporter")
+	})
+}
@@ -0,0 +1,59 @@
+package web
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/infra/web/webserver"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+)
+
+// testAPI serves the REST API as the service does, with the same routes and middleware, over
+// in-memory repositories.
+type testAPI struct {
+	ProductRepository *database.InMemoryProductRepository
+	WebhookRepository *database.InMemoryWebhookRepository
+
+	handler http.Handler
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	productRepository := database.NewInMemoryProductRepository()
+	auditRepository := database.NewInMemoryProductAuditRepository()
+	outboxRepository := database.NewInMemoryProductOutboxRepository()
+	transactionManager := database.NewInMemoryTransactionManager()
+	webhookRepository := database.NewInMemoryWebhookRepository()
+
+	server := webserver.NewWebServer("", logger)
+	NewWebProductHandler(
+		usecase.NewCreateProductUseCase(productRepository, auditRepository, outboxRepository, transactionManager, logger),
+		productRepository, auditRepository, outboxRepository, transactionManager,
+		usecase.NewCursorCodec([]byte("test secret")), logger,
+	).RegisterRoutes(server)
+	NewWebWebhookHandler(webhookRepository, logger).RegisterRoutes(server)
+
+	return &testAPI{
+		ProductRepository: productRepository,
+		WebhookRepository: webhookRepository,
+		handler:           server.Handler(),
+	}
+}
+
+// do sends a request to path, relative to the base path of the API, with headers given as
+// name/value pairs.
+func (a *testAPI) do(method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, "/api/v1"+path, strings.NewReader(body))
+	for i := 0; i+1 < len(headers); i += 2 {
+		request.Header.Set(headers[i], headers[i+1])
+	}
+	recorder := httptest.NewRecorder()
+	a.handler.ServeHTTP(recorder, request)
+	return recorder
+}
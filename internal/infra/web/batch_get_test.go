@@ -0,0 +1,46 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchGet(t *testing.T) {
+	api := newTestAPI(t)
+	var ids []string
+	for _, name := range []string{"Product A", "Product B"} {
+		product, err := entity.NewProduct(name, "description", 10)
+		require.NoError(t, err)
+		require.NoError(t, api.ProductRepository.Create(context.Background(), product))
+		ids = append(ids, product.GetID())
+	}
+
+	do := func(body string) *httptest.ResponseRecorder {
+		return api.do(http.MethodPost, "/products:batchGet", body)
+	}
+
+	response := do(`{"ids": ["` + strings.ToUpper(ids[1]) + `", "missing", "` + ids[0] + `"]}`)
+	require.Equal(t, http.StatusOK, response.Code)
+	var output usecase.ProductBatchGetOutputDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &output))
+	require.Len(t, output.Products, 2)
+	require.Equal(t, "Product B", output.Products[0].Name)
+	require.Equal(t, ids[1], output.Products[0].ID, "ids are returned in canonical form")
+	require.Equal(t, "Product A", output.Products[1].Name)
+	require.Equal(t, []string{"missing"}, output.MissingIDs)
+
+	response = do(`{"ids": ["missing"]}`)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.JSONEq(t, `{"products": [], "missing_ids": ["missing"]}`, response.Body.String())
+
+	require.Equal(t, http.StatusBadRequest, do(`{"ids": []}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(`{"ids": `).Code)
+}
@@ -0,0 +1,171 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/logging"
+)
+
+const problemContentType = "application/problem+json"
+
+// StatusClientClosedRequest is the non-standard status, popularized by nginx, reported when the
+// client goes away before the response is ready. The client never sees it, but logs and metrics do.
+const StatusClientClosedRequest = 499
+
+// Problem types returned in ProblemDetails.Type, one per domain error.
+const (
+	ProblemTypeInvalidInput       = "/problems/invalid-input"
+	ProblemTypeNotFound           = "/problems/not-found"
+	ProblemTypeConflict           = "/problems/conflict"
+	ProblemTypePreconditionFailed = "/problems/precondition-failed"
+	ProblemTypeValidation         = "/problems/validation-error"
+	ProblemTypeTimeout            = "/problems/timeout"
+	ProblemTypeClientClosed       = "/problems/client-closed-request"
+	ProblemTypeInternal           = "about:blank"
+)
+
+// ProblemDetails represents an RFC 7807 error response
+type ProblemDetails struct {
+	Type     string       `json:"type" example:"/problems/validation-error"`
+	Title    string       `json:"title" example:"Unprocessable Entity"`
+	Status   int          `json:"status" example:"422"`
+	Detail   string       `json:"detail,omitempty" example:"name cannot be empty"`
+	Instance string       `json:"instance,omitempty" example:"/api/v1/products"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes a validation failure on a single field
+type FieldError struct {
+	Field   string `json:"field" example:"name"`
+	Message string `json:"message" example:"name cannot be empty"`
+}
+
+// statusCodeFromError maps domain errors to the HTTP status code returned to the client.
+// Anything not recognized as a domain error is treated as an internal server error.
+func statusCodeFromError(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, entity.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, entity.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, entity.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return StatusClientClosedRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func problemTypeFromStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return ProblemTypeInvalidInput
+	case http.StatusNotFound:
+		return ProblemTypeNotFound
+	case http.StatusConflict:
+		return ProblemTypeConflict
+	case http.StatusPreconditionFailed:
+		return ProblemTypePreconditionFailed
+	case http.StatusUnprocessableEntity:
+		return ProblemTypeValidation
+	case http.StatusGatewayTimeout:
+		return ProblemTypeTimeout
+	case StatusClientClosedRequest:
+		return ProblemTypeClientClosed
+	default:
+		return ProblemTypeInternal
+	}
+}
+
+// writeError writes err as a ProblemDetails response using the status code of its domain error.
+// Details of internal errors are not exposed to the client. Errors caused by the request context
+// ending, which database drivers do not always wrap, are reported as timeouts or cancellations.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := statusCodeFromError(err)
+	if ctxErr := r.Context().Err(); status == http.StatusInternalServerError && ctxErr != nil {
+		status = statusCodeFromError(ctxErr)
+	}
+
+	detail := err.Error()
+	switch status {
+	case http.StatusInternalServerError:
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "internal error",
+			"method", r.Method, "path", r.URL.Path, "error", err)
+		detail = "an unexpected error occurred"
+	case http.StatusGatewayTimeout:
+		detail = "the request took too long to complete"
+	case StatusClientClosedRequest:
+		detail = "the client closed the request"
+	}
+	problem := newProblemDetails(r, status, detail)
+	problem.Errors = fieldErrors(err)
+	writeProblem(w, r, problem)
+}
+
+// writeErrorMessage writes a ProblemDetails response for errors raised by the handler itself,
+// such as malformed request bodies or missing path parameters.
+func writeErrorMessage(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	writeProblem(w, r, newProblemDetails(r, status, detail))
+}
+
+func newProblemDetails(r *http.Request, status int, detail string) ProblemDetails {
+	return ProblemDetails{
+		Type:     problemTypeFromStatus(status),
+		Title:    statusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	}
+}
+
+func statusText(status int) string {
+	if status == StatusClientClosedRequest {
+		return "Client Closed Request"
+	}
+	return http.StatusText(status)
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, problem ProblemDetails) {
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(problem.Status)
+	err := json.NewEncoder(w).Encode(problem)
+	if err != nil {
+		logging.FromContext(r.Context()).WarnContext(r.Context(), "error encoding error response", "error", err)
+	}
+}
+
+// fieldErrors collects every ValidationError wrapped in err, including errors joined with errors.Join.
+func fieldErrors(err error) []FieldError {
+	var result []FieldError
+	var walk func(error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		if validationErr, ok := err.(*entity.ValidationError); ok {
+			result = append(result, FieldError{Field: validationErr.Field, Message: validationErr.Message})
+			return
+		}
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				walk(inner)
+			}
+		case interface{ Unwrap() error }:
+			walk(e.Unwrap())
+		}
+	}
+	walk(err)
+	return result
+}
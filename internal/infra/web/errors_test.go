@@ -0,0 +1,99 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusCodeFromError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"Invalid input", fmt.Errorf("%w: bad page", entity.ErrInvalidInput), http.StatusBadRequest},
+		{"Not found", entity.NewProductNotFoundError("123"), http.StatusNotFound},
+		{"Conflict", fmt.Errorf("%w: duplicated", entity.ErrConflict), http.StatusConflict},
+		{"Precondition failed", entity.ErrPreconditionFailed, http.StatusPreconditionFailed},
+		{"Validation", entity.NewValidationError("name", "name cannot be empty"), http.StatusUnprocessableEntity},
+		{"Deadline exceeded", fmt.Errorf("listing products: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{"Canceled", context.Canceled, StatusClientClosedRequest},
+		{"Unknown", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, statusCodeFromError(tc.err))
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	t.Run("Validation error", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/products", nil)
+		w := httptest.NewRecorder()
+
+		writeError(w, r, entity.NewValidationError("name", "name cannot be empty"))
+
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		require.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+		var problem ProblemDetails
+		require.Nil(t, json.NewDecoder(w.Body).Decode(&problem))
+		require.Equal(t, ProblemDetails{
+			Type:     ProblemTypeValidation,
+			Title:    "Unprocessable Entity",
+			Status:   http.StatusUnprocessableEntity,
+			Detail:   "name cannot be empty",
+			Instance: "/api/v1/products",
+			Errors:   []FieldError{{Field: "name", Message: "name cannot be empty"}},
+		}, problem)
+	})
+
+	t.Run("Internal error hides detail", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/products", nil)
+		w := httptest.NewRecorder()
+
+		writeError(w, r, errors.New("pq: connection refused"))
+
+		var problem ProblemDetails
+		require.Nil(t, json.NewDecoder(w.Body).Decode(&problem))
+		require.Equal(t, http.StatusInternalServerError, problem.Status)
+		require.Equal(t, "an unexpected error occurred", problem.Detail)
+		require.Empty(t, problem.Errors)
+	})
+
+	t.Run("Driver error after the request deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 0)
+		defer cancel()
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/products", nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		writeError(w, r, errors.New("pq: canceling statement due to user request"))
+
+		var problem ProblemDetails
+		require.Nil(t, json.NewDecoder(w.Body).Decode(&problem))
+		require.Equal(t, http.StatusGatewayTimeout, w.Code)
+		require.Equal(t, ProblemTypeTimeout, problem.Type)
+		require.Equal(t, "the request took too long to complete", problem.Detail)
+	})
+
+	t.Run("Joined validation errors", func(t *testing.T) {
+		err := errors.Join(
+			entity.NewValidationError("name", "name cannot be empty"),
+			entity.NewValidationError("price", "price must be greater or equal zero"),
+		)
+		require.Equal(t, []FieldError{
+			{Field: "name", Message: "name cannot be empty"},
+			{Field: "price", Message: "price must be greater or equal zero"},
+		}, fieldErrors(err))
+	})
+}
@@ -0,0 +1,58 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	usecase "github.com/HaroldoFV/product-service/internal/usecase"
+)
+
+// productETag is the strong entity tag of a product at version.
+func productETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// writeProduct writes a single product along with its ETag, for clients to send back in
+// If-Match and If-None-Match headers.
+func writeProduct(w http.ResponseWriter, r *http.Request, status int, output usecase.ProductOutputDTO) {
+	w.Header().Set("ETag", productETag(output.Version))
+	writeJSON(w, r, status, output)
+}
+
+// parseIfMatch returns the versions listed in an If-Match header. ok is false when the header
+// is absent or "*", which any existing product matches. Weak tags never match, as If-Match
+// requires a strong comparison, and neither do tags that are not product versions, so the
+// result may be empty even though ok is true.
+func parseIfMatch(header string) (versions []int, ok bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) || len(tag) < 2 {
+			continue
+		}
+		version, err := strconv.Atoi(tag[1 : len(tag)-1])
+		if err == nil {
+			versions = append(versions, version)
+		}
+	}
+	return versions, true
+}
+
+// ifNoneMatch reports whether an If-None-Match header matches etag, using the weak comparison
+// required by RFC 9110 for that header.
+func ifNoneMatch(header, etag string) bool {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package web
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	testCases := []struct {
+		header      string
+		versions    []int
+		conditional bool
+	}{
+		{"", nil, false},
+		{"*", nil, false},
+		{`"3"`, []int{3}, true},
+		{`"3", "5"`, []int{3, 5}, true},
+		{`W/"3"`, nil, true},
+		{`"abc"`, nil, true},
+		{`3`, nil, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.header, func(t *testing.T) {
+			versions, conditional := parseIfMatch(tc.header)
+			require.Equal(t, tc.versions, versions)
+			require.Equal(t, tc.conditional, conditional)
+		})
+	}
+}
+
+func TestIfNoneMatch(t *testing.T) {
+	require.True(t, ifNoneMatch(`"2"`, `"2"`))
+	require.True(t, ifNoneMatch(`W/"2"`, `"2"`))
+	require.True(t, ifNoneMatch(`"1", "2"`, `"2"`))
+	require.True(t, ifNoneMatch("*", `"2"`))
+	require.False(t, ifNoneMatch(`"1"`, `"2"`))
+	require.False(t, ifNoneMatch("", `"2"`))
+}
+
+func TestProductETags(t *testing.T) {
+	repository := database.NewInMemoryProductRepository()
+	product, err := entity.NewProduct("Product", "description", 10)
+	require.NoError(t, err)
+	require.NoError(t, repository.Create(context.Background(), product))
+
+	handler := NewWebProductHandler(nil, repository, database.NewInMemoryProductAuditRepository(), database.NewInMemoryProductOutboxRepository(), database.NewInMemoryTransactionManager(), nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	router := chi.NewRouter()
+	router.Get("/products/{id}", handler.GetProduct)
+	router.Put("/products/{id}", handler.Update)
+
+	do := func(method, body string, headers map[string]string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, "/products/"+product.GetID(), strings.NewReader(body))
+		for name, value := range headers {
+			request.Header.Set(name, value)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	response := do(http.MethodGet, "", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, `"1"`, response.Header().Get("ETag"))
+
+	response = do(http.MethodGet, "", map[string]string{"If-None-Match": `"1"`})
+	require.Equal(t, http.StatusNotModified, response.Code)
+	require.Equal(t, `"1"`, response.Header().Get("ETag"))
+	require.Empty(t, response.Body.String())
+
+	update := `{"name":"Updated","price":20}`
+	response = do(http.MethodPut, update, map[string]string{"If-Match": `"1"`})
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, `"2"`, response.Header().Get("ETag"))
+
+	// The product changed since version 1 was read
+	response = do(http.MethodPut, update, map[string]string{"If-Match": `"1"`})
+	require.Equal(t, http.StatusPreconditionFailed, response.Code)
+	require.Contains(t, response.Body.String(), ProblemTypePreconditionFailed)
+
+	response = do(http.MethodPut, update, map[string]string{"If-Match": `W/"2"`})
+	require.Equal(t, http.StatusPreconditionFailed, response.Code)
+
+	response = do(http.MethodGet, "", map[string]string{"If-None-Match": `"1"`})
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, `"2"`, response.Header().Get("ETag"))
+}
@@ -0,0 +1,76 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/infra/web/webserver"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+	"github.com/stretchr/testify/require"
+)
+
+// slowRepository takes delay to read every product of an export.
+type slowRepository struct {
+	*database.InMemoryProductRepository
+	delay time.Duration
+}
+
+func (r *slowRepository) ForEach(ctx context.Context, filter domain.ProductFilter, fn func(*entity.Product) error) error {
+	return r.InMemoryProductRepository.ForEach(ctx, filter, func(product *entity.Product) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.delay):
+		}
+		return fn(product)
+	})
+}
+
+func TestExportOutlastsTimeouts(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repository := &slowRepository{InMemoryProductRepository: database.NewInMemoryProductRepository(), delay: 2 * time.Millisecond}
+	for i := 0; i < 150; i++ {
+		product, err := entity.NewProduct(fmt.Sprintf("Product %d", i), "description", 10)
+		require.NoError(t, err)
+		require.NoError(t, repository.Create(context.Background(), product))
+	}
+
+	server := webserver.NewWebServer("", logger)
+	server.RequestTimeout = timeout
+	handler := NewWebProductHandler(nil, repository, nil, nil, database.NewInMemoryTransactionManager(),
+		usecase.NewCursorCodec([]byte("test secret")), logger)
+	handler.ExportWriteTimeout = timeout
+	handler.RegisterRoutes(server)
+
+	httpServer := httptest.NewUnstartedServer(server.Handler())
+	httpServer.Config.WriteTimeout = timeout
+	httpServer.Start()
+	defer httpServer.Close()
+
+	// The export takes about 300ms, three times the request and write timeouts
+	start := time.Now()
+	response, err := http.Get(httpServer.URL + "/api/v1/products/export?format=ndjson")
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	lines := 0
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() {
+		lines++
+	}
+	require.NoError(t, scanner.Err())
+	require.Equal(t, 150, lines, "the export is complete")
+	require.Greater(t, time.Since(start), 2*timeout)
+}
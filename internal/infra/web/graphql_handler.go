@@ -0,0 +1,138 @@
+package web
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/infra/graph"
+	"github.com/HaroldoFV/product-service/internal/infra/logging"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// queryCacheSize is the number of parsed queries kept by the GraphQL server.
+const queryCacheSize = 1000
+
+// GraphQL error codes, returned in the code extension of the errors raised by the resolvers.
+// They follow the HTTP status codes of the REST API.
+const (
+	GraphQLCodeInvalidInput       = "BAD_USER_INPUT"
+	GraphQLCodeNotFound           = "NOT_FOUND"
+	GraphQLCodeConflict           = "CONFLICT"
+	GraphQLCodePreconditionFailed = "PRECONDITION_FAILED"
+	GraphQLCodeValidation         = "VALIDATION_ERROR"
+	GraphQLCodeTimeout            = "TIMEOUT"
+	GraphQLCodeClientClosed       = "CLIENT_CLOSED_REQUEST"
+	GraphQLCodeInternal           = "INTERNAL_SERVER_ERROR"
+)
+
+// WebGraphQLHandler serves the GraphQL API over the product use cases.
+type WebGraphQLHandler struct {
+	ProductRepository domain.ProductRepositoryInterface
+
+	server *handler.Server
+}
+
+// NewWebGraphQLHandler returns a handler resolving queries with resolver. Queries whose
+// complexity is above complexityLimit are rejected before running; zero disables the limit.
+func NewWebGraphQLHandler(resolver *graph.Resolver, complexityLimit int) *WebGraphQLHandler {
+	server := handler.New(graph.NewSchema(resolver))
+	server.AddTransport(transport.GET{})
+	server.AddTransport(transport.POST{})
+	server.SetQueryCache(lru.New(queryCacheSize))
+	server.SetErrorPresenter(presentGraphQLError)
+	server.SetRecoverFunc(recoverGraphQL)
+	server.Use(extension.Introspection{})
+	if complexityLimit > 0 {
+		server.Use(extension.FixedComplexityLimit(complexityLimit))
+	}
+
+	return &WebGraphQLHandler{
+		ProductRepository: resolver.ProductRepository,
+		server:            server,
+	}
+}
+
+// Serve godoc
+// @Summary GraphQL endpoint
+// @Description Run a GraphQL query or mutation over the product catalog. GET only runs queries. The schema is
+// @Description available through introspection. Errors raised by the resolvers carry a code extension
+// @Description (BAD_USER_INPUT, NOT_FOUND, CONFLICT, PRECONDITION_FAILED, VALIDATION_ERROR, TIMEOUT,
+// @Description INTERNAL_SERVER_ERROR) and VALIDATION_ERROR also the errors of each field.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param query query string false "query, for GET requests"
+// @Success 200 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Router /graphql [post]
+// @Router /graphql [get]
+func (h *WebGraphQLHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	// Loaders cache what they load, so each request gets its own.
+	ctx := graph.WithLoaders(r.Context(), graph.NewLoaders(h.ProductRepository))
+	h.server.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// presentGraphQLError sets the code extension of the errors raised by the resolvers. Internal
+// errors are logged and replaced by a generic message, as writeError does for the REST API.
+func presentGraphQLError(ctx context.Context, err error) *gqlerror.Error {
+	// Resolver errors reach the presenter wrapped with their path. Errors raised by gqlgen itself,
+	// such as parse errors, wrap nothing and already carry their code.
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if gqlErr.Err == nil {
+		return gqlErr
+	}
+	err = gqlErr.Err
+	status := statusCodeFromError(err)
+	switch status {
+	case http.StatusInternalServerError:
+		logging.FromContext(ctx).ErrorContext(ctx, "internal error", "path", gqlErr.Path.String(), "error", err)
+		gqlErr.Message = "an unexpected error occurred"
+	case http.StatusGatewayTimeout:
+		gqlErr.Message = "the request took too long to complete"
+	case StatusClientClosedRequest:
+		gqlErr.Message = "the client closed the request"
+	}
+	gqlErr.Extensions = map[string]any{"code": graphQLCodeFromStatus(status)}
+	if fields := fieldErrors(err); len(fields) > 0 {
+		gqlErr.Extensions["errors"] = fields
+	}
+	return gqlErr
+}
+
+// recoverGraphQL logs a panic raised by a resolver and reports it as an internal error.
+func recoverGraphQL(ctx context.Context, recovered any) error {
+	logging.FromContext(ctx).ErrorContext(ctx, "panic resolving GraphQL field",
+		"path", graphql.GetPath(ctx).String(), "panic", recovered)
+	return &gqlerror.Error{
+		Message:    "an unexpected error occurred",
+		Path:       graphql.GetPath(ctx),
+		Extensions: map[string]any{"code": GraphQLCodeInternal},
+	}
+}
+
+func graphQLCodeFromStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return GraphQLCodeInvalidInput
+	case http.StatusNotFound:
+		return GraphQLCodeNotFound
+	case http.StatusConflict:
+		return GraphQLCodeConflict
+	case http.StatusPreconditionFailed:
+		return GraphQLCodePreconditionFailed
+	case http.StatusUnprocessableEntity:
+		return GraphQLCodeValidation
+	case http.StatusGatewayTimeout:
+		return GraphQLCodeTimeout
+	case StatusClientClosedRequest:
+		return GraphQLCodeClientClosed
+	default:
+		return GraphQLCodeInternal
+	}
+}
@@ -0,0 +1,243 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/infra/graph"
+	"github.com/stretchr/testify/require"
+)
+
+type graphQLResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Path       []any          `json:"path"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+type graphQLProduct struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Price   float64 `json:"price"`
+	Status  string  `json:"status"`
+	Version int     `json:"version"`
+}
+
+// countingProductRepository counts the lookups by id.
+type countingProductRepository struct {
+	domain.ProductRepositoryInterface
+	getByIDCalls  atomic.Int32
+	getByIDsCalls atomic.Int32
+}
+
+func (r *countingProductRepository) GetByID(ctx context.Context, id string) (*entity.Product, error) {
+	r.getByIDCalls.Add(1)
+	return r.ProductRepositoryInterface.GetByID(ctx, id)
+}
+
+func (r *countingProductRepository) GetByIDs(ctx context.Context, ids []string) ([]*entity.Product, error) {
+	r.getByIDsCalls.Add(1)
+	return r.ProductRepositoryInterface.GetByIDs(ctx, ids)
+}
+
+// failingProductRepository fails every listing.
+type failingProductRepository struct {
+	domain.ProductRepositoryInterface
+}
+
+func (r failingProductRepository) List(context.Context, domain.ProductFilter, int, int, domain.ProductSort) ([]*entity.Product, int, error) {
+	return nil, 0, errors.New("connection refused")
+}
+
+func newGraphQLTestHandler(productRepository domain.ProductRepositoryInterface, complexityLimit int) *WebGraphQLHandler {
+	resolver := graph.NewResolver(productRepository, database.NewInMemoryProductAuditRepository(),
+		database.NewInMemoryProductOutboxRepository(), database.NewInMemoryTransactionManager(),
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return NewWebGraphQLHandler(resolver, complexityLimit)
+}
+
+func postGraphQL(t *testing.T, handler *WebGraphQLHandler, query string, variables map[string]any) (int, graphQLResponse) {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	require.NoError(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	handler.Serve(recorder, request)
+
+	var response graphQLResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response), recorder.Body.String())
+	return recorder.Code, response
+}
+
+func TestGraphQL(t *testing.T) {
+	handler := newGraphQLTestHandler(database.NewInMemoryProductRepository(), 1000)
+
+	code, response := postGraphQL(t, handler, `mutation($input: ProductInput!) {
+		createProduct(input: $input) { id name price status version }
+	}`, map[string]any{"input": map[string]any{"name": "Keyboard", "price": 100}})
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, response.Errors)
+	var created graphQLProduct
+	require.NoError(t, json.Unmarshal(response.Data["createProduct"], &created))
+	require.Equal(t, "Keyboard", created.Name)
+	require.Equal(t, entity.DISABLED, created.Status)
+	require.Equal(t, 1, created.Version)
+
+	_, response = postGraphQL(t, handler, `mutation { createProduct(input: {name: "Mouse", price: 20}) { id } }`, nil)
+	require.Empty(t, response.Errors)
+
+	_, response = postGraphQL(t, handler, `query($id: ID!) { product(id: $id) { name } missing: product(id: "missing") { name } }`,
+		map[string]any{"id": created.ID})
+	require.Empty(t, response.Errors)
+	require.JSONEq(t, `{"name": "Keyboard"}`, string(response.Data["product"]))
+	require.JSONEq(t, `null`, string(response.Data["missing"]))
+
+	_, response = postGraphQL(t, handler, `{
+		products(filter: {minPrice: 50}, sort: "-price", limit: 1) { products { name } totalCount page limit totalPages }
+	}`, nil)
+	require.Empty(t, response.Errors)
+	require.JSONEq(t, `{"products": [{"name": "Keyboard"}], "totalCount": 1, "page": 1, "limit": 1, "totalPages": 1}`,
+		string(response.Data["products"]))
+
+	_, response = postGraphQL(t, handler, `mutation($id: ID!) {
+		updateProduct(id: $id, input: {name: "Mechanical keyboard", price: 150}, expectedVersion: 1) { name version }
+	}`, map[string]any{"id": created.ID})
+	require.Empty(t, response.Errors)
+	require.JSONEq(t, `{"name": "Mechanical keyboard", "version": 2}`, string(response.Data["updateProduct"]))
+
+	_, response = postGraphQL(t, handler, `mutation($id: ID!) { enableProduct(id: $id) { status } }`, map[string]any{"id": created.ID})
+	require.Empty(t, response.Errors)
+	require.JSONEq(t, `{"status": "enabled"}`, string(response.Data["enableProduct"]))
+	_, response = postGraphQL(t, handler, `mutation($id: ID!) { disableProduct(id: $id) { status } }`, map[string]any{"id": created.ID})
+	require.Empty(t, response.Errors)
+	require.JSONEq(t, `{"status": "disabled"}`, string(response.Data["disableProduct"]))
+
+	_, response = postGraphQL(t, handler, `mutation($id: ID!) { deleteProduct(id: $id) }`, map[string]any{"id": created.ID})
+	require.Empty(t, response.Errors)
+	require.JSONEq(t, `true`, string(response.Data["deleteProduct"]))
+	_, response = postGraphQL(t, handler, `query($id: ID!) { product(id: $id) { name } }`, map[string]any{"id": created.ID})
+	require.Empty(t, response.Errors)
+	require.JSONEq(t, `null`, string(response.Data["product"]))
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	handler := newGraphQLTestHandler(database.NewInMemoryProductRepository(), 1000)
+	_, response := postGraphQL(t, handler, `mutation { createProduct(input: {name: "Keyboard", price: 100}) { id } }`, nil)
+	require.Empty(t, response.Errors)
+	var created graphQLProduct
+	require.NoError(t, json.Unmarshal(response.Data["createProduct"], &created))
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		code      string
+	}{
+		{name: "invalid input", query: `{ products(filter: {status: "archived"}) { totalCount } }`, code: GraphQLCodeInvalidInput},
+		{name: "not found", query: `mutation { enableProduct(id: "missing") { id } }`, code: GraphQLCodeNotFound},
+		{
+			name:      "precondition failed",
+			query:     `mutation($id: ID!) { updateProduct(id: $id, input: {name: "Keyboard", price: 100}, expectedVersion: 7) { id } }`,
+			variables: map[string]any{"id": created.ID},
+			code:      GraphQLCodePreconditionFailed,
+		},
+		{name: "validation", query: `mutation { createProduct(input: {name: "", price: -1}) { id } }`, code: GraphQLCodeValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, response := postGraphQL(t, handler, tt.query, tt.variables)
+			require.Equal(t, http.StatusOK, code)
+			require.Len(t, response.Errors, 1)
+			require.Equal(t, tt.code, response.Errors[0].Extensions["code"])
+			require.NotEmpty(t, response.Errors[0].Path)
+		})
+	}
+
+	_, response = postGraphQL(t, handler, `mutation { createProduct(input: {name: "", price: 10}) { id } }`, nil)
+	require.Equal(t, []any{map[string]any{"field": "name", "message": "name cannot be empty"}}, response.Errors[0].Extensions["errors"])
+}
+
+func TestGraphQLMasksInternalErrors(t *testing.T) {
+	handler := newGraphQLTestHandler(failingProductRepository{database.NewInMemoryProductRepository()}, 1000)
+
+	_, response := postGraphQL(t, handler, `{ products { totalCount } }`, nil)
+	require.Len(t, response.Errors, 1)
+	require.Equal(t, GraphQLCodeInternal, response.Errors[0].Extensions["code"])
+	require.Equal(t, "an unexpected error occurred", response.Errors[0].Message)
+}
+
+func TestGraphQLGetOnlyRunsQueries(t *testing.T) {
+	handler := newGraphQLTestHandler(database.NewInMemoryProductRepository(), 1000)
+	get := func(query string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.Serve(recorder, httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(query), nil))
+		return recorder
+	}
+
+	response := get(`{ products { totalCount } }`)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.JSONEq(t, `{"data": {"products": {"totalCount": 0}}}`, response.Body.String())
+
+	response = get(`mutation { createProduct(input: {name: "Keyboard", price: 100}) { id } }`)
+	require.Equal(t, http.StatusNotAcceptable, response.Code)
+}
+
+func TestGraphQLComplexityLimit(t *testing.T) {
+	handler := newGraphQLTestHandler(database.NewInMemoryProductRepository(), 100)
+
+	code, response := postGraphQL(t, handler, `{ products(limit: 10) { products { id name } } }`, nil)
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, response.Errors)
+
+	_, response = postGraphQL(t, handler, `{ products(limit: 100) { products { id name } } }`, nil)
+	require.Empty(t, response.Data)
+	require.Len(t, response.Errors, 1)
+	require.Equal(t, "COMPLEXITY_LIMIT_EXCEEDED", response.Errors[0].Extensions["code"])
+}
+
+func TestGraphQLBatchesProductLoads(t *testing.T) {
+	repository := &countingProductRepository{ProductRepositoryInterface: database.NewInMemoryProductRepository()}
+	handler := newGraphQLTestHandler(repository, 1000)
+	var ids []string
+	for _, name := range []string{"Keyboard", "Mouse"} {
+		_, response := postGraphQL(t, handler, `mutation($name: String!) { createProduct(input: {name: $name, price: 10}) { id } }`,
+			map[string]any{"name": name})
+		require.Empty(t, response.Errors)
+		var created graphQLProduct
+		require.NoError(t, json.Unmarshal(response.Data["createProduct"], &created))
+		ids = append(ids, created.ID)
+	}
+
+	_, response := postGraphQL(t, handler, `query($a: ID!, $upper: ID!, $b: ID!) {
+		a: product(id: $a) { name }
+		again: product(id: $a) { price }
+		upper: product(id: $upper) { id }
+		b: product(id: $b) { name }
+		empty: product(id: "") { name }
+		missing: product(id: "missing") { name }
+	}`, map[string]any{"a": ids[0], "upper": strings.ToUpper(ids[0]), "b": ids[1]})
+	require.Empty(t, response.Errors)
+	require.JSONEq(t, `{"name": "Keyboard"}`, string(response.Data["a"]))
+	require.JSONEq(t, `{"price": 10}`, string(response.Data["again"]))
+	require.JSONEq(t, `{"id": "`+ids[0]+`"}`, string(response.Data["upper"]))
+	require.JSONEq(t, `{"name": "Mouse"}`, string(response.Data["b"]))
+	require.JSONEq(t, `null`, string(response.Data["empty"]))
+	require.JSONEq(t, `null`, string(response.Data["missing"]))
+	require.EqualValues(t, 0, repository.getByIDCalls.Load())
+	require.EqualValues(t, 1, repository.getByIDsCalls.Load())
+}
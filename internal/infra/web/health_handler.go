@@ -0,0 +1,116 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Statuses reported by the health endpoints.
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusFail = "fail"
+)
+
+// HealthCheck is a dependency probed by the readiness endpoint.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type HealthResponse struct {
+	Status string                       `json:"status"`
+	Checks map[string]HealthCheckResult `json:"checks,omitempty"`
+}
+
+type HealthCheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// WebHealthHandler serves the liveness and readiness probes used by orchestrators.
+type WebHealthHandler struct {
+	Checks []HealthCheck
+	// Timeout bounds each check run by Readiness.
+	Timeout time.Duration
+
+	shuttingDown atomic.Bool
+}
+
+func NewWebHealthHandler(timeout time.Duration, checks ...HealthCheck) *WebHealthHandler {
+	return &WebHealthHandler{
+		Checks:  checks,
+		Timeout: timeout,
+	}
+}
+
+// SetShuttingDown makes Readiness fail from now on, so that load balancers stop routing
+// traffic to the instance while in-flight requests drain.
+func (h *WebHealthHandler) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Liveness reports that the process is up. It checks no dependency and keeps succeeding
+// during shutdown, so the orchestrator never restarts an instance that is draining.
+func (h *WebHealthHandler) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, r, http.StatusOK, HealthResponse{Status: HealthStatusOK})
+}
+
+// Readiness runs every check concurrently and reports the status and latency of each one,
+// answering 503 when any of them fails or the service is shutting down.
+func (h *WebHealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	response := HealthResponse{
+		Status: HealthStatusOK,
+		Checks: make(map[string]HealthCheckResult, len(h.Checks)+1),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range h.Checks {
+		wg.Add(1)
+		go func(check HealthCheck) {
+			defer wg.Done()
+			result := h.run(r.Context(), check)
+			mu.Lock()
+			response.Checks[check.Name] = result
+			mu.Unlock()
+		}(check)
+	}
+	wg.Wait()
+
+	if h.shuttingDown.Load() {
+		response.Checks["shutdown"] = HealthCheckResult{Status: HealthStatusFail, Error: "service is shutting down"}
+	}
+
+	status := http.StatusOK
+	for _, result := range response.Checks {
+		if result.Status != HealthStatusOK {
+			response.Status = HealthStatusFail
+			status = http.StatusServiceUnavailable
+		}
+	}
+	writeJSON(w, r, status, response)
+}
+
+func (h *WebHealthHandler) run(ctx context.Context, check HealthCheck) HealthCheckResult {
+	if h.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := check.Check(ctx)
+	result := HealthCheckResult{
+		Status:    HealthStatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = HealthStatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
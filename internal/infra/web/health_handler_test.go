@@ -0,0 +1,69 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebHealthHandler(t *testing.T) {
+	healthy := HealthCheck{Name: "database", Check: func(ctx context.Context) error { return nil }}
+	failing := HealthCheck{Name: "migrations", Check: func(ctx context.Context) error {
+		return errors.New("database is at version 2, expected 3")
+	}}
+	slow := HealthCheck{Name: "database", Check: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+
+	probe := func(handler http.HandlerFunc) (int, HealthResponse) {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var response HealthResponse
+		require.Nil(t, json.NewDecoder(w.Body).Decode(&response))
+		return w.Code, response
+	}
+
+	t.Run("Liveness", func(t *testing.T) {
+		h := NewWebHealthHandler(time.Second, failing)
+		h.SetShuttingDown()
+		status, response := probe(h.Liveness)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, HealthStatusOK, response.Status)
+	})
+
+	t.Run("Ready", func(t *testing.T) {
+		status, response := probe(NewWebHealthHandler(time.Second, healthy).Readiness)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, HealthStatusOK, response.Status)
+		require.Equal(t, HealthStatusOK, response.Checks["database"].Status)
+	})
+
+	t.Run("Failing dependency", func(t *testing.T) {
+		status, response := probe(NewWebHealthHandler(time.Second, healthy, failing).Readiness)
+		require.Equal(t, http.StatusServiceUnavailable, status)
+		require.Equal(t, HealthStatusFail, response.Status)
+		require.Equal(t, HealthStatusOK, response.Checks["database"].Status)
+		require.Equal(t, "database is at version 2, expected 3", response.Checks["migrations"].Error)
+	})
+
+	t.Run("Check timeout", func(t *testing.T) {
+		status, response := probe(NewWebHealthHandler(10*time.Millisecond, slow).Readiness)
+		require.Equal(t, http.StatusServiceUnavailable, status)
+		require.Equal(t, context.DeadlineExceeded.Error(), response.Checks["database"].Error)
+	})
+
+	t.Run("Shutting down", func(t *testing.T) {
+		h := NewWebHealthHandler(time.Second, healthy)
+		h.SetShuttingDown()
+		status, response := probe(h.Readiness)
+		require.Equal(t, http.StatusServiceUnavailable, status)
+		require.Equal(t, HealthStatusFail, response.Checks["shutdown"].Status)
+	})
+}
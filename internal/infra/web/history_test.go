@@ -0,0 +1,57 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/web/webserver"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	api := newTestAPI(t)
+	product, err := entity.NewProduct("Product", "description", 10)
+	require.NoError(t, err)
+	require.NoError(t, api.ProductRepository.Create(context.Background(), product))
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		return api.do(method, path, body, webserver.ActorHeader, "alice")
+	}
+	history := func(target string) PaginatedProductHistoryResponse {
+		response := do(http.MethodGet, target, "")
+		require.Equal(t, http.StatusOK, response.Code)
+		var page PaginatedProductHistoryResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &page))
+		return page
+	}
+
+	// Created before changes were audited
+	page := history("/products/" + product.GetID() + "/history")
+	require.Equal(t, 0, page.TotalCount)
+	require.Empty(t, page.Entries)
+
+	require.Equal(t, http.StatusOK, do(http.MethodPut, "/products/"+product.GetID(), `{"name": "Renamed", "description": "description", "price": 10}`).Code)
+	require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/products/"+product.GetID(), "").Code)
+
+	page = history("/products/" + product.GetID() + "/history?limit=1")
+	require.Equal(t, 2, page.TotalCount)
+	require.Equal(t, 2, page.TotalPages)
+	require.Len(t, page.Entries, 1)
+	require.Equal(t, entity.AuditDelete, page.Entries[0].Operation)
+
+	page = history("/products/" + product.GetID() + "/history?page=2&limit=1")
+	update := page.Entries[0]
+	require.Equal(t, entity.AuditUpdate, update.Operation)
+	require.Equal(t, "alice", update.Actor)
+	require.Equal(t, 2, update.Version)
+	require.Len(t, update.Changes, 1)
+	require.Equal(t, entity.AuditFieldName, update.Changes[0].Field)
+	require.Equal(t, "Product", update.Changes[0].Before)
+	require.Equal(t, "Renamed", update.Changes[0].After)
+
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/products/00000000-0000-0000-0000-000000000000/history", "").Code)
+}
@@ -0,0 +1,61 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchProduct(t *testing.T) {
+	api := newTestAPI(t)
+	repository := api.ProductRepository
+	product, err := entity.NewProduct("Product", "description", 10)
+	require.NoError(t, err)
+	require.NoError(t, repository.Create(context.Background(), product))
+
+	do := func(contentType, body string, headers ...string) *httptest.ResponseRecorder {
+		return api.do(http.MethodPatch, "/products/"+product.GetID(), body, append([]string{"Content-Type", contentType}, headers...)...)
+	}
+
+	response := do("application/merge-patch+json", `{"price": 15.5}`)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, `"2"`, response.Header().Get("ETag"))
+	require.Contains(t, response.Body.String(), `"price":15.5`)
+	require.Contains(t, response.Body.String(), `"name":"Product"`)
+
+	response = do("application/json-patch+json; charset=utf-8",
+		`[{"op": "replace", "path": "/name", "value": "Renamed"}]`, "If-Match", `"2"`)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, `"3"`, response.Header().Get("ETag"))
+	require.Contains(t, response.Body.String(), `"name":"Renamed"`)
+
+	response = do("application/merge-patch+json", `{"price": 1}`, "If-Match", `"2"`)
+	require.Equal(t, http.StatusPreconditionFailed, response.Code)
+
+	response = do("application/json-patch+json", `[{"op": "test", "path": "/price", "value": 1}]`)
+	require.Equal(t, http.StatusConflict, response.Code)
+
+	response = do("application/merge-patch+json", `{"status": "enabled"}`)
+	require.Equal(t, http.StatusUnprocessableEntity, response.Code)
+
+	response = do("application/merge-patch+json", `{"price":`)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = do("application/json", `{"price": 1}`)
+	require.Equal(t, http.StatusUnsupportedMediaType, response.Code)
+	require.Contains(t, response.Header().Get("Accept-Patch"), "application/merge-patch+json")
+
+	response = do("application/merge-patch+json", `{"price": 1`+strings.Repeat(" ", maxPatchSize)+`}`)
+	require.Equal(t, http.StatusRequestEntityTooLarge, response.Code)
+
+	stored, err := repository.GetByID(context.Background(), product.GetID())
+	require.NoError(t, err)
+	require.Equal(t, "Renamed", stored.GetName())
+	require.Equal(t, 15.5, stored.GetPrice())
+	require.Equal(t, 3, stored.GetVersion())
+}
@@ -2,26 +2,77 @@ package web
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
 	usecase "github.com/HaroldoFV/product-service/internal/usecase"
 	"github.com/go-chi/chi"
+	"io"
+	"log/slog"
+	"mime"
 	"net/http"
 	"strconv"
+	"time"
 )
 
+// maxImportSize is the largest payload accepted by Import.
+const maxImportSize = 32 << 20
+
+// maxPatchSize is the largest payload accepted by Patch.
+const maxPatchSize = 1 << 20
+
+// patchFormats maps the media types accepted by Patch to use case formats.
+var patchFormats = map[string]string{
+	"application/merge-patch+json": usecase.PatchFormatMerge,
+	"application/json-patch+json":  usecase.PatchFormatJSON,
+}
+
+// importFormats maps the media types accepted by Import to use case formats.
+var importFormats = map[string]string{
+	"text/csv":             usecase.ImportFormatCSV,
+	"application/x-ndjson": usecase.ImportFormatNDJSON,
+	"application/jsonl":    usecase.ImportFormatNDJSON,
+}
+
+// exportContentTypes maps the formats accepted by Export to response content types.
+var exportContentTypes = map[string]string{
+	usecase.ExportFormatCSV:    "text/csv",
+	usecase.ExportFormatNDJSON: "application/x-ndjson",
+	usecase.ExportFormatJSON:   "application/json",
+}
+
 type WebProductHandler struct {
 	CreateProductUseCase *usecase.CreateProductUseCase
 	ProductRepository    domain.ProductRepositoryInterface
+	AuditRepository      domain.ProductAuditRepositoryInterface
+	OutboxRepository     domain.OutboxRepositoryInterface
+	TransactionManager   domain.TransactionManager
+	CursorCodec          *usecase.CursorCodec
+	// ExportWriteTimeout bounds the write of each part of an export rather than the whole
+	// response, which can take as long as the catalog requires. Zero leaves the write deadline of
+	// the server as is.
+	ExportWriteTimeout time.Duration
+	Logger             *slog.Logger
 }
 
 func NewWebProductHandler(
 	createProductUseCase *usecase.CreateProductUseCase,
 	productRepository domain.ProductRepositoryInterface,
+	auditRepository domain.ProductAuditRepositoryInterface,
+	outboxRepository domain.OutboxRepositoryInterface,
+	transactionManager domain.TransactionManager,
+	cursorCodec *usecase.CursorCodec,
+	logger *slog.Logger,
 ) *WebProductHandler {
 	return &WebProductHandler{
 		CreateProductUseCase: createProductUseCase,
 		ProductRepository:    productRepository,
+		AuditRepository:      auditRepository,
+		OutboxRepository:     outboxRepository,
+		TransactionManager:   transactionManager,
+		CursorCodec:          cursorCodec,
+		Logger:               logger,
 	}
 }
 
@@ -33,72 +84,76 @@ func NewWebProductHandler(
 // @Produce  json
 // @Param product body usecase.ProductInputDTO true "Create product"
 // @Success 201 {object} usecase.ProductOutputDTO
+// @Header 201 {string} ETag "version of the product"
+// @Failure 400 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Failure 422 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
 // @Router /products [post]
 func (h *WebProductHandler) Create(w http.ResponseWriter, r *http.Request) {
-	fmt.Println("Received request to /products")
-
 	var dto usecase.ProductInputDTO
 	err := json.NewDecoder(r.Body).Decode(&dto)
 	if err != nil {
-		fmt.Println("Error decoding request body:", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	fmt.Printf("Received product: %+v\n", dto)
-
-	output, err := h.CreateProductUseCase.Execute(dto)
-	if err != nil {
-		fmt.Println("Error executing create product use case:", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeErrorMessage(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	err = json.NewEncoder(w).Encode(output)
+	output, err := h.CreateProductUseCase.Execute(r.Context(), dto)
 	if err != nil {
-		fmt.Println("Error encoding response:", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	fmt.Println("Product created successfully")
+	writeProduct(w, r, http.StatusCreated, output)
 }
 
 // List Products godoc
 // @Summary List Products
-// @Description List Products
+// @Description List Products using offset pagination (page/limit), answered with a PaginatedProductResponse.
+// @Description When the cursor parameter is present, even empty, keyset pagination is used instead: page is ignored
+// @Description and the response is a CursorPaginatedProductResponse, {"products": [...], "limit": 10,
+// @Description "next_cursor": "...", "prev_cursor": "..."}, without total_count, page and total_pages. Pass
+// @Description next_cursor or prev_cursor as cursor to follow; each is omitted when there is no page that way.
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param page query int false "page number" default(1)
 // @Param limit query int false "limit" default(10)
-// @Param sort query string false "sort field" default("id")
-// @Success 200 {object} PaginatedProductResponse
-// @Failure 404 {object} Error
-// @Failure 500 {object} Error
+// @Param sort query string false "comma separated sort fields (id, name, price, status, created_at), prefix with - for descending, e.g. -price,name" default(id)
+// @Param status query string false "product status" Enums(enabled, disabled)
+// @Param min_price query number false "minimum price"
+// @Param max_price query number false "maximum price"
+// @Param name_prefix query string false "case-insensitive name prefix"
+// @Param q query string false "full-text search over name and description"
+// @Param cursor query string false "opaque cursor from a previous response; empty for the first page"
+// @Success 200 {object} PaginatedProductResponse "offset pagination; a CursorPaginatedProductResponse when cursor is present"
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
 // @Router /products [get]
 func (h *WebProductHandler) GetProducts(w http.ResponseWriter, r *http.Request) {
-	page, err := strconv.Atoi(r.URL.Query().Get("page"))
-	if err != nil || page < 1 {
-		page = 1
-	}
-
-	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
-	if err != nil || limit < 1 {
-		limit = 10
-	}
+	page, limit := parsePage(r)
 
 	sort := r.URL.Query().Get("sort")
 	if sort == "" {
 		sort = "id"
 	}
 
+	filter, err := parseProductFilter(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if r.URL.Query().Has("cursor") {
+		h.getProductsByCursor(w, r, filter, sort, limit)
+		return
+	}
+
 	listProductsUseCase := usecase.NewListProductsUseCase(h.ProductRepository)
-	output, totalCount, err := listProductsUseCase.Execute(page, limit, sort)
+	output, totalCount, err := listProductsUseCase.Execute(r.Context(), filter, page, limit, sort)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -110,168 +165,537 @@ func (h *WebProductHandler) GetProducts(w http.ResponseWriter, r *http.Request)
 		TotalPages: (totalCount + limit - 1) / limit,
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	err = json.NewEncoder(w).Encode(response)
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+func (h *WebProductHandler) getProductsByCursor(w http.ResponseWriter, r *http.Request, filter domain.ProductFilter, sort string, limit int) {
+	listProductsByCursorUseCase := usecase.NewListProductsByCursorUseCase(h.ProductRepository, h.CursorCodec)
+	output, err := listProductsByCursorUseCase.Execute(r.Context(), usecase.ProductCursorListInputDTO{
+		Filter: filter,
+		Sort:   sort,
+		Cursor: r.URL.Query().Get("cursor"),
+		Limit:  limit,
+	})
 	if err != nil {
-		fmt.Println("Error encoding response:", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
+	response := CursorPaginatedProductResponse{
+		Products:   output.Products,
+		Limit:      limit,
+		NextCursor: output.NextCursor,
+		PrevCursor: output.PrevCursor,
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
 }
 
 // Update Product godoc
 // @Summary Update Product
-// @Description Update Product
+// @Description Update Product. Send the ETag of the product read in If-Match to make sure nobody changed it
+// @Description in the meantime: a mismatch answers 412. Without If-Match, a product changed while the update
+// @Description is in progress answers 409.
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID" Format(uuid)
+// @Param If-Match header string false "ETag of the version being updated"
 // @Param request body usecase.ProductUpdateInputDTO true "product Request"
 // @Success 200 {object} usecase.ProductOutputDTO
-// @Failure 404 {object} Error
-// @Failure 500 {object} Error
+// @Header 200 {string} ETag "version of the product"
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Failure 412 {object} ProblemDetails
+// @Failure 422 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
 // @Router /products/{id} [put]
 func (h *WebProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		err := json.NewEncoder(w).Encode(Error{Message: "missing product ID"})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
+		writeErrorMessage(w, r, http.StatusBadRequest, "missing product ID")
 		return
 	}
 
 	var dto usecase.ProductUpdateInputDTO
 	err := json.NewDecoder(r.Body).Decode(&dto)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		err := json.NewEncoder(w).Encode(Error{Message: err.Error()})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
+		writeErrorMessage(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	dto.ID = id
+	var conditional bool
+	dto.IfMatch, conditional = parseIfMatch(r.Header.Get("If-Match"))
+	if conditional && len(dto.IfMatch) == 0 {
+		writeErrorMessage(w, r, http.StatusPreconditionFailed, "If-Match does not match any version of the product")
+		return
+	}
 
-	updateProductUseCase := usecase.NewUpdateProductUseCase(h.ProductRepository)
-	output, err := updateProductUseCase.Execute(dto)
+	updateProductUseCase := usecase.NewUpdateProductUseCase(h.ProductRepository, h.AuditRepository, h.OutboxRepository, h.TransactionManager, h.Logger)
+	output, err := updateProductUseCase.Execute(r.Context(), dto)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == fmt.Sprintf("product with id %s not found", id) {
-			status = http.StatusNotFound
-		}
-		w.WriteHeader(status)
-		err := json.NewEncoder(w).Encode(Error{Message: err.Error()})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
+	}
+
+	writeProduct(w, r, http.StatusOK, output)
+}
+
+// Patch Product godoc
+// @Summary Partially update a product
+// @Description Change only some fields of a product with a JSON Merge Patch (application/merge-patch+json, RFC 7396)
+// @Description or a JSON Patch (application/json-patch+json, RFC 6902) applied to the product as returned by the API.
+// @Description Only name, description and price can change. A JSON Patch that cannot be applied, such as a failed
+// @Description test operation, answers 409. If-Match works as in PUT.
+// @Tags products
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Param id path string true "Product ID" Format(uuid)
+// @Param If-Match header string false "ETag of the version being updated"
+// @Param patch body object true "merge patch object or array of JSON Patch operations"
+// @Success 200 {object} usecase.ProductOutputDTO
+// @Header 200 {string} ETag "version of the product"
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Failure 412 {object} ProblemDetails
+// @Failure 413 {object} ProblemDetails
+// @Failure 415 {object} ProblemDetails
+// @Failure 422 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
+// @Router /products/{id} [patch]
+func (h *WebProductHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeErrorMessage(w, r, http.StatusBadRequest, "missing product ID")
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	format, ok := patchFormats[mediaType]
+	if !ok {
+		w.Header().Set("Accept-Patch", "application/merge-patch+json, application/json-patch+json")
+		writeErrorMessage(w, r, http.StatusUnsupportedMediaType,
+			"content type must be application/merge-patch+json or application/json-patch+json")
+		return
+	}
+
+	patch, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPatchSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeErrorMessage(w, r, http.StatusRequestEntityTooLarge, err.Error())
+			return
 		}
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(output)
+	ifMatch, conditional := parseIfMatch(r.Header.Get("If-Match"))
+	if conditional && len(ifMatch) == 0 {
+		writeErrorMessage(w, r, http.StatusPreconditionFailed, "If-Match does not match any version of the product")
+		return
+	}
+
+	patchProductUseCase := usecase.NewPatchProductUseCase(h.ProductRepository, h.AuditRepository, h.OutboxRepository, h.TransactionManager, h.Logger)
+	output, err := patchProductUseCase.Execute(r.Context(), usecase.ProductPatchInputDTO{
+		ID:      id,
+		Format:  format,
+		Patch:   patch,
+		IfMatch: ifMatch,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeProduct(w, r, http.StatusOK, output)
 }
 
 // GetProduct godoc
 // @Summary Get Product
-// @Description Get Product
+// @Description Get Product. The response carries an ETag; sending it back in If-None-Match answers 304 while
+// @Description the product is unchanged.
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID" Format(uuid)
+// @Param If-None-Match header string false "ETag of the cached version"
 // @Success 200 {object} usecase.ProductOutputDTO
-// @Failure 404 {object} Error
-// @Failure 500 {object} Error
+// @Header 200 {string} ETag "version of the product"
+// @Success 304
+// @Failure 404 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
 // @Router /products/{id} [get]
 func (h *WebProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		err := json.NewEncoder(w).Encode(Error{Message: "missing product ID"})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
+		writeErrorMessage(w, r, http.StatusBadRequest, "missing product ID")
 		return
 	}
 
 	getProductUseCase := usecase.NewGetProductUseCase(h.ProductRepository)
-	output, err := getProductUseCase.Execute(id)
+	output, err := getProductUseCase.Execute(r.Context(), id)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == fmt.Sprintf("product with id %s not found", id) {
-			status = http.StatusNotFound
-		}
-		w.WriteHeader(status)
-		err := json.NewEncoder(w).Encode(Error{Message: err.Error()})
+		writeError(w, r, err)
+		return
+	}
+
+	if etag := productETag(output.Version); ifNoneMatch(r.Header.Get("If-None-Match"), etag) {
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeProduct(w, r, http.StatusOK, output)
+}
+
+// Delete Product godoc
+// @Summary Delete a product
+// @Description Move a product to the trash, from where it can be restored until it is purged.
+// @Description With permanent=true the product is removed for good, even if it is already in the trash.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID" Format(uuid)
+// @Param permanent query bool false "remove the product for good" default(false)
+// @Success 204
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
+// @Router /products/{id} [delete]
+func (h *WebProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeErrorMessage(w, r, http.StatusBadRequest, "missing product ID")
+		return
+	}
+
+	permanent := false
+	if value := r.URL.Query().Get("permanent"); value != "" {
+		var err error
+		permanent, err = strconv.ParseBool(value)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorMessage(w, r, http.StatusBadRequest, fmt.Sprintf("invalid permanent value %q", value))
+			return
 		}
-		return
 	}
 
-	response := usecase.ProductOutputDTO{
-		ID:          output.ID,
-		Name:        output.Name,
-		Description: output.Description,
-		Price:       output.Price,
-		Status:      output.Status,
+	deleteProductUseCase := usecase.NewDeleteProductUseCase(h.ProductRepository, h.AuditRepository, h.OutboxRepository, h.TransactionManager, h.Logger)
+	err := deleteProductUseCase.Execute(r.Context(), id, permanent)
+	if err != nil {
+		writeError(w, r, err)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	err = json.NewEncoder(w).Encode(response)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List Deleted Products godoc
+// @Summary List deleted products
+// @Description List the products in the trash, most recently deleted first
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param page query int false "page number" default(1)
+// @Param limit query int false "limit" default(10)
+// @Success 200 {object} PaginatedProductResponse
+// @Failure 500 {object} ProblemDetails
+// @Router /products/trash [get]
+func (h *WebProductHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePage(r)
+
+	listDeletedProductsUseCase := usecase.NewListDeletedProductsUseCase(h.ProductRepository)
+	output, totalCount, err := listDeletedProductsUseCase.Execute(r.Context(), page, limit)
 	if err != nil {
-		fmt.Println("Error encoding response:", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
+	response := PaginatedProductResponse{
+		Products:   output,
+		TotalCount: totalCount,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (totalCount + limit - 1) / limit,
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
 }
 
-// Delete Product godoc
-// @Summary Delete a product
-// @Description Delete a Product
+// Batch Get Products godoc
+// @Summary Get several products at once
+// @Description Get up to 100 products by id with a single lookup. Products come back in the order their ids were
+// @Description requested, repeated ids once, and the ids without a product, including those in the trash and those
+// @Description that are not UUIDs, are listed in missing_ids as sent. Ids are compared in canonical UUID form.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param ids body usecase.ProductBatchGetInputDTO true "Product IDs"
+// @Success 200 {object} usecase.ProductBatchGetOutputDTO
+// @Failure 400 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
+// @Router /products:batchGet [post]
+func (h *WebProductHandler) BatchGet(w http.ResponseWriter, r *http.Request) {
+	var dto usecase.ProductBatchGetInputDTO
+	err := json.NewDecoder(r.Body).Decode(&dto)
+	if err != nil {
+		writeErrorMessage(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	getProductsByIDsUseCase := usecase.NewGetProductsByIDsUseCase(h.ProductRepository)
+	output, err := getProductsByIDsUseCase.Execute(r.Context(), dto)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, output)
+}
+
+// Restore Product godoc
+// @Summary Restore a deleted product
+// @Description Take a product out of the trash
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID" Format(uuid)
-// @Success 200
-// @Failure 404 {object} Error
-// @Failure 500 {object} Error
-// @Router /products/{id} [delete]
-func (h *WebProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
+// @Success 200 {object} usecase.ProductOutputDTO
+// @Header 200 {string} ETag "version of the product"
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
+// @Router /products/{id}/restore [post]
+func (h *WebProductHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeErrorMessage(w, r, http.StatusBadRequest, "missing product ID")
+		return
+	}
+
+	restoreProductUseCase := usecase.NewRestoreProductUseCase(h.ProductRepository, h.AuditRepository, h.OutboxRepository, h.TransactionManager, h.Logger)
+	output, err := restoreProductUseCase.Execute(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeProduct(w, r, http.StatusOK, output)
+}
+
+// Product History godoc
+// @Summary Get the history of a product
+// @Description List the changes made to a product, the most recent first: who made each change, when, and the
+// @Description fields changed with their values before and after. The history of deleted products is kept.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID" Format(uuid)
+// @Param page query int false "page number" default(1)
+// @Param limit query int false "limit" default(10)
+// @Success 200 {object} PaginatedProductHistoryResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
+// @Router /products/{id}/history [get]
+func (h *WebProductHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		err := json.NewEncoder(w).Encode(Error{Message: "missing product ID"})
+		writeErrorMessage(w, r, http.StatusBadRequest, "missing product ID")
+		return
+	}
+	page, limit := parsePage(r)
+
+	getProductHistoryUseCase := usecase.NewGetProductHistoryUseCase(h.ProductRepository, h.AuditRepository)
+	output, totalCount, err := getProductHistoryUseCase.Execute(r.Context(), id, page, limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := PaginatedProductHistoryResponse{
+		Entries:    output,
+		TotalCount: totalCount,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (totalCount + limit - 1) / limit,
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// Import Products godoc
+// @Summary Import products
+// @Description Bulk import products from a CSV (header with name, description and price columns) or
+// @Description NDJSON (one product object per line) payload. Valid rows are created in a single transaction
+// @Description and a per-row report is returned. With dry_run=true rows are only validated.
+// @Tags products
+// @Accept text/csv,application/x-ndjson
+// @Produce json
+// @Param payload body string true "CSV or NDJSON payload"
+// @Param dry_run query bool false "only validate the payload" default(false)
+// @Success 200 {object} usecase.ProductImportOutputDTO
+// @Failure 400 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Failure 413 {object} ProblemDetails
+// @Failure 415 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
+// @Router /products/import [post]
+func (h *WebProductHandler) Import(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	format, ok := importFormats[mediaType]
+	if !ok {
+		writeErrorMessage(w, r, http.StatusUnsupportedMediaType, "content type must be text/csv or application/x-ndjson")
+		return
+	}
+
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(value)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorMessage(w, r, http.StatusBadRequest, "dry_run must be a boolean")
+			return
 		}
-		return
 	}
 
-	deleteProductUseCase := usecase.NewDeleteProductUseCase(h.ProductRepository)
-	err := deleteProductUseCase.Execute(id)
+	importProductsUseCase := usecase.NewImportProductsUseCase(h.ProductRepository, h.AuditRepository, h.OutboxRepository, h.TransactionManager, h.Logger)
+	output, err := importProductsUseCase.Execute(r.Context(), usecase.ProductImportInputDTO{
+		Format: format,
+		Reader: http.MaxBytesReader(w, r.Body, maxImportSize),
+		DryRun: dryRun,
+	})
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == fmt.Sprintf("product with id %s not found", id) {
-			status = http.StatusNotFound
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeErrorMessage(w, r, http.StatusRequestEntityTooLarge, err.Error())
+			return
 		}
-		w.WriteHeader(status)
-		err := json.NewEncoder(w).Encode(Error{Message: err.Error()})
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, output)
+}
+
+// Export Products godoc
+// @Summary Export products
+// @Description Stream the whole catalog, optionally filtered by status, as CSV, NDJSON or a JSON array
+// @Tags products
+// @Produce text/csv,application/x-ndjson,json
+// @Param format query string false "export format" Enums(csv, ndjson, json) default(json)
+// @Param status query string false "product status" Enums(enabled, disabled)
+// @Param min_price query number false "minimum price"
+// @Param max_price query number false "maximum price"
+// @Param name_prefix query string false "case-insensitive name prefix"
+// @Param q query string false "full-text search over name and description"
+// @Success 200 {array} usecase.ProductOutputDTO
+// @Failure 400 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
+// @Router /products/export [get]
+func (h *WebProductHandler) Export(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = usecase.ExportFormatJSON
+	}
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		writeErrorMessage(w, r, http.StatusBadRequest, "format must be csv, ndjson or json")
+		return
+	}
+
+	filter, err := parseProductFilter(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="products.%s"`, format))
+
+	writer := &exportWriter{ResponseWriter: w, controller: http.NewResponseController(w), timeout: h.ExportWriteTimeout}
+	exportProductsUseCase := usecase.NewExportProductsUseCase(h.ProductRepository)
+	err = exportProductsUseCase.Execute(r.Context(), usecase.ProductExportInputDTO{
+		Format: format,
+		Filter: filter,
+		Writer: writer,
+	})
+	if err != nil {
+		if !writer.written {
+			w.Header().Del("Content-Disposition")
+			writeError(w, r, err)
+			return
 		}
+		// The response is already streaming, so the client only sees a truncated body.
+		h.Logger.ErrorContext(r.Context(), "error exporting products", "format", format, "error", err)
+	}
+}
+
+// Enable Product godoc
+// @Summary Enable a product
+// @Description Enable a product so it can be sold. Only products with a price greater than zero can be enabled.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID" Format(uuid)
+// @Success 200 {object} usecase.ProductOutputDTO
+// @Header 200 {string} ETag "version of the product"
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Failure 422 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
+// @Router /products/{id}/enable [post]
+func (h *WebProductHandler) Enable(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeErrorMessage(w, r, http.StatusBadRequest, "missing product ID")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNoContent)
+	enableProductUseCase := usecase.NewEnableProductUseCase(h.ProductRepository, h.AuditRepository, h.OutboxRepository, h.TransactionManager, h.Logger)
+	output, err := enableProductUseCase.Execute(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeProduct(w, r, http.StatusOK, output)
+}
+
+// Disable Product godoc
+// @Summary Disable a product
+// @Description Disable a product so it can no longer be sold
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID" Format(uuid)
+// @Success 200 {object} usecase.ProductOutputDTO
+// @Header 200 {string} ETag "version of the product"
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
+// @Router /products/{id}/disable [post]
+func (h *WebProductHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeErrorMessage(w, r, http.StatusBadRequest, "missing product ID")
+		return
+	}
+
+	disableProductUseCase := usecase.NewDisableProductUseCase(h.ProductRepository, h.AuditRepository, h.OutboxRepository, h.TransactionManager, h.Logger)
+	output, err := disableProductUseCase.Execute(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeProduct(w, r, http.StatusOK, output)
 }
 
 type PaginatedProductResponse struct {
@@ -282,7 +706,80 @@ type PaginatedProductResponse struct {
 	TotalPages int                        `json:"total_pages"`
 }
 
-// Error represents an error response
-type Error struct {
-	Message string `json:"message"`
+type PaginatedProductHistoryResponse struct {
+	Entries    []usecase.ProductAuditEntryOutputDTO `json:"entries"`
+	TotalCount int                                  `json:"total_count"`
+	Page       int                                  `json:"page"`
+	Limit      int                                  `json:"limit"`
+	TotalPages int                                  `json:"total_pages"`
+}
+
+type CursorPaginatedProductResponse struct {
+	Products   []usecase.ProductOutputDTO `json:"products"`
+	Limit      int                        `json:"limit"`
+	NextCursor string                     `json:"next_cursor,omitempty"`
+	PrevCursor string                     `json:"prev_cursor,omitempty"`
+}
+
+// exportWriter records whether any byte of a streamed response has been written,
+// which tells Export if an error can still be reported with a proper status code. When timeout
+// is set, every write gets that long from now to reach the client.
+type exportWriter struct {
+	http.ResponseWriter
+	controller *http.ResponseController
+	timeout    time.Duration
+	written    bool
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	w.written = true
+	if w.timeout > 0 {
+		// Not every writer supports deadlines, and the export goes on without one
+		_ = w.controller.SetWriteDeadline(time.Now().Add(w.timeout))
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *exportWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// parsePage reads the page and limit of offset pagination from the query string, falling back
+// to the first page of 10 products.
+func parsePage(r *http.Request) (int, int) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+	return page, limit
+}
+
+// parseProductFilter reads the filters shared by GetProducts and Export from the query string.
+func parseProductFilter(r *http.Request) (domain.ProductFilter, error) {
+	query := r.URL.Query()
+	filter := domain.ProductFilter{
+		Status:     query.Get("status"),
+		NamePrefix: query.Get("name_prefix"),
+		Query:      query.Get("q"),
+	}
+
+	for param, target := range map[string]**float64{"min_price": &filter.MinPrice, "max_price": &filter.MaxPrice} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		price, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return domain.ProductFilter{}, fmt.Errorf("%w: %s must be a number", entity.ErrInvalidInput, param)
+		}
+		*target = &price
+	}
+	return filter, nil
 }
@@ -0,0 +1,19 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/HaroldoFV/product-service/internal/infra/logging"
+)
+
+// writeJSON writes v as a JSON response. Once the status code has been sent an encoding
+// failure can no longer be reported to the client, so it is only logged.
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		logging.FromContext(r.Context()).WarnContext(r.Context(), "error encoding response", "error", err)
+	}
+}
@@ -0,0 +1,47 @@
+package web
+
+import "net/http"
+
+// RouteRegistry is where the handlers register their routes, relative to the base path of the
+// API. It is implemented by webserver.WebServer.
+type RouteRegistry interface {
+	AddHandler(method, path string, handler http.HandlerFunc)
+	// AddStreamingHandler registers a route whose response is streamed for as long as it takes,
+	// with no request timeout.
+	AddStreamingHandler(method, path string, handler http.HandlerFunc)
+}
+
+// RegisterRoutes registers the product routes on registry.
+func (h *WebProductHandler) RegisterRoutes(registry RouteRegistry) {
+	registry.AddHandler(http.MethodPost, "/products", h.Create)
+	registry.AddHandler(http.MethodGet, "/products", h.GetProducts)
+	registry.AddHandler(http.MethodPost, "/products/import", h.Import)
+	registry.AddStreamingHandler(http.MethodGet, "/products/export", h.Export)
+	registry.AddHandler(http.MethodGet, "/products/trash", h.GetTrash)
+	registry.AddHandler(http.MethodPost, "/products:batchGet", h.BatchGet)
+	registry.AddHandler(http.MethodPut, "/products/{id}", h.Update)
+	registry.AddHandler(http.MethodPatch, "/products/{id}", h.Patch)
+	registry.AddHandler(http.MethodGet, "/products/{id}", h.GetProduct)
+	registry.AddHandler(http.MethodDelete, "/products/{id}", h.Delete)
+	registry.AddHandler(http.MethodPost, "/products/{id}/enable", h.Enable)
+	registry.AddHandler(http.MethodPost, "/products/{id}/disable", h.Disable)
+	registry.AddHandler(http.MethodPost, "/products/{id}/restore", h.Restore)
+	registry.AddHandler(http.MethodGet, "/products/{id}/history", h.GetHistory)
+}
+
+// RegisterRoutes registers the webhook routes on registry.
+func (h *WebWebhookHandler) RegisterRoutes(registry RouteRegistry) {
+	registry.AddHandler(http.MethodPost, "/webhooks", h.Create)
+	registry.AddHandler(http.MethodGet, "/webhooks", h.GetWebhooks)
+	registry.AddHandler(http.MethodGet, "/webhooks/dead-letters", h.GetDeadLetters)
+	registry.AddHandler(http.MethodGet, "/webhooks/{id}", h.GetWebhook)
+	registry.AddHandler(http.MethodDelete, "/webhooks/{id}", h.Delete)
+	registry.AddHandler(http.MethodGet, "/webhooks/{id}/deliveries", h.GetDeliveries)
+	registry.AddHandler(http.MethodPost, "/webhooks/{id}/deliveries/{deliveryID}/retry", h.RetryDelivery)
+}
+
+// RegisterRoutes registers the GraphQL endpoint on registry. GET only runs queries.
+func (h *WebGraphQLHandler) RegisterRoutes(registry RouteRegistry) {
+	registry.AddHandler(http.MethodPost, "/graphql", h.Serve)
+	registry.AddHandler(http.MethodGet, "/graphql", h.Serve)
+}
@@ -0,0 +1,52 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrash(t *testing.T) {
+	api := newTestAPI(t)
+	product, err := entity.NewProduct("Product", "description", 10)
+	require.NoError(t, err)
+	require.NoError(t, api.ProductRepository.Create(context.Background(), product))
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		return api.do(method, path, "")
+	}
+	trash := func() PaginatedProductResponse {
+		response := do(http.MethodGet, "/products/trash")
+		require.Equal(t, http.StatusOK, response.Code)
+		var page PaginatedProductResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &page))
+		return page
+	}
+
+	require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/products/"+product.GetID()).Code)
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/products/"+product.GetID()).Code)
+	require.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/products/"+product.GetID()).Code)
+
+	page := trash()
+	require.Equal(t, 1, page.TotalCount)
+	require.Equal(t, product.GetID(), page.Products[0].ID)
+	require.NotNil(t, page.Products[0].DeletedAt)
+
+	response := do(http.MethodPost, "/products/"+product.GetID()+"/restore")
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, `"3"`, response.Header().Get("ETag"))
+	require.NotContains(t, response.Body.String(), "deleted_at")
+	require.Equal(t, http.StatusConflict, do(http.MethodPost, "/products/"+product.GetID()+"/restore").Code)
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/products/"+product.GetID()).Code)
+	require.Equal(t, 0, trash().TotalCount)
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodDelete, "/products/"+product.GetID()+"?permanent=maybe").Code)
+	require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/products/"+product.GetID()+"?permanent=true").Code)
+	require.Equal(t, http.StatusNotFound, do(http.MethodPost, "/products/"+product.GetID()+"/restore").Code)
+	require.Equal(t, 0, trash().TotalCount)
+}
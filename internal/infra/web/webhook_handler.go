@@ -0,0 +1,235 @@
+package web
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+	"github.com/go-chi/chi"
+)
+
+// WebWebhookHandler manages the webhooks notified of product changes.
+type WebWebhookHandler struct {
+	WebhookRepository domain.WebhookRepositoryInterface
+	Logger            *slog.Logger
+}
+
+func NewWebWebhookHandler(webhookRepository domain.WebhookRepositoryInterface, logger *slog.Logger) *WebWebhookHandler {
+	return &WebWebhookHandler{
+		WebhookRepository: webhookRepository,
+		Logger:            logger,
+	}
+}
+
+type PaginatedWebhookDeliveryResponse struct {
+	Deliveries []usecase.WebhookDeliveryOutputDTO `json:"deliveries"`
+	TotalCount int                                `json:"total_count"`
+	Page       int                                `json:"page"`
+	Limit      int                                `json:"limit"`
+	TotalPages int                                `json:"total_pages"`
+}
+
+// Create Webhook godoc
+// @Summary Register a webhook
+// @Description Subscribe a URL to product events. Every delivery is a POST of the event signed with the secret:
+// @Description X-Webhook-Signature is sha256= followed by the hex HMAC-SHA256 of X-Webhook-Timestamp, a dot and the body.
+// @Description Leave events empty to receive every event type and secret empty to have one generated. The secret is
+// @Description only returned in this response.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body usecase.WebhookInputDTO true "Create webhook"
+// @Success 201 {object} usecase.WebhookOutputDTO
+// @Failure 400 {object} ProblemDetails
+// @Failure 422 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
+// @Router /webhooks [post]
+func (h *WebWebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var dto usecase.WebhookInputDTO
+	err := json.NewDecoder(r.Body).Decode(&dto)
+	if err != nil {
+		writeErrorMessage(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	createWebhookUseCase := usecase.NewCreateWebhookUseCase(h.WebhookRepository, h.Logger)
+	output, err := createWebhookUseCase.Execute(r.Context(), dto)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, output)
+}
+
+// List Webhooks godoc
+// @Summary List webhooks
+// @Description List every webhook, oldest first, without their secrets
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Success 200 {array} usecase.WebhookOutputDTO
+// @Failure 500 {object} ProblemDetails
+// @Router /webhooks [get]
+func (h *WebWebhookHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	listWebhooksUseCase := usecase.NewListWebhooksUseCase(h.WebhookRepository)
+	output, err := listWebhooksUseCase.Execute(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, output)
+}
+
+// Get Webhook godoc
+// @Summary Get a webhook
+// @Description Get a webhook by ID, without its secret
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID" Format(uuid)
+// @Success 200 {object} usecase.WebhookOutputDTO
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
+// @Router /webhooks/{id} [get]
+func (h *WebWebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeErrorMessage(w, r, http.StatusBadRequest, "missing webhook ID")
+		return
+	}
+
+	getWebhookUseCase := usecase.NewGetWebhookUseCase(h.WebhookRepository)
+	output, err := getWebhookUseCase.Execute(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, output)
+}
+
+// Delete Webhook godoc
+// @Summary Delete a webhook
+// @Description Unsubscribe a webhook. Its deliveries, including the pending ones, are removed too.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID" Format(uuid)
+// @Success 204
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
+// @Router /webhooks/{id} [delete]
+func (h *WebWebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeErrorMessage(w, r, http.StatusBadRequest, "missing webhook ID")
+		return
+	}
+
+	deleteWebhookUseCase := usecase.NewDeleteWebhookUseCase(h.WebhookRepository, h.Logger)
+	if err := deleteWebhookUseCase.Execute(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Webhook Deliveries godoc
+// @Summary List the deliveries of a webhook
+// @Description Delivery log of a webhook, most recent first, with the outcome of the last attempt of each delivery
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID" Format(uuid)
+// @Param status query string false "delivery status" Enums(pending, delivered, dead)
+// @Param page query int false "page number" default(1)
+// @Param limit query int false "limit" default(10)
+// @Success 200 {object} PaginatedWebhookDeliveryResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebWebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeErrorMessage(w, r, http.StatusBadRequest, "missing webhook ID")
+		return
+	}
+	h.listDeliveries(w, r, id, r.URL.Query().Get("status"))
+}
+
+// Dead Letters godoc
+// @Summary List the dead letters
+// @Description List the deliveries of every webhook that failed all their attempts, most recent first
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param page query int false "page number" default(1)
+// @Param limit query int false "limit" default(10)
+// @Success 200 {object} PaginatedWebhookDeliveryResponse
+// @Failure 500 {object} ProblemDetails
+// @Router /webhooks/dead-letters [get]
+func (h *WebWebhookHandler) GetDeadLetters(w http.ResponseWriter, r *http.Request) {
+	h.listDeliveries(w, r, "", entity.DeliveryDead)
+}
+
+func (h *WebWebhookHandler) listDeliveries(w http.ResponseWriter, r *http.Request, webhookID, status string) {
+	page, limit := parsePage(r)
+
+	listWebhookDeliveriesUseCase := usecase.NewListWebhookDeliveriesUseCase(h.WebhookRepository)
+	output, totalCount, err := listWebhookDeliveriesUseCase.Execute(r.Context(), webhookID, status, page, limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := PaginatedWebhookDeliveryResponse{
+		Deliveries: output,
+		TotalCount: totalCount,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (totalCount + limit - 1) / limit,
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// Retry Webhook Delivery godoc
+// @Summary Retry a dead delivery
+// @Description Take a delivery out of the dead letters, to be sent again right away with a new series of attempts
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID" Format(uuid)
+// @Param deliveryID path string true "Delivery ID" Format(uuid)
+// @Success 200 {object} usecase.WebhookDeliveryOutputDTO
+// @Failure 400 {object} ProblemDetails
+// @Failure 404 {object} ProblemDetails
+// @Failure 409 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
+// @Router /webhooks/{id}/deliveries/{deliveryID}/retry [post]
+func (h *WebWebhookHandler) RetryDelivery(w http.ResponseWriter, r *http.Request) {
+	id, deliveryID := chi.URLParam(r, "id"), chi.URLParam(r, "deliveryID")
+	if id == "" || deliveryID == "" {
+		writeErrorMessage(w, r, http.StatusBadRequest, "missing webhook or delivery ID")
+		return
+	}
+
+	retryWebhookDeliveryUseCase := usecase.NewRetryWebhookDeliveryUseCase(h.WebhookRepository, h.Logger)
+	output, err := retryWebhookDeliveryUseCase.Execute(r.Context(), id, deliveryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, output)
+}
@@ -0,0 +1,71 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooks(t *testing.T) {
+	api := newTestAPI(t)
+	repository := api.WebhookRepository
+	deliveries := func(target string) PaginatedWebhookDeliveryResponse {
+		response := api.do(http.MethodGet, target, "")
+		require.Equal(t, http.StatusOK, response.Code)
+		var page PaginatedWebhookDeliveryResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &page))
+		return page
+	}
+
+	response := api.do(http.MethodPost, "/webhooks", `{"url": "https://example.com/hooks", "events": ["product.created"], "secret": "0123456789abcdef"}`)
+	require.Equal(t, http.StatusCreated, response.Code)
+	var created usecase.WebhookOutputDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &created))
+	require.Equal(t, "0123456789abcdef", created.Secret)
+
+	require.Equal(t, http.StatusUnprocessableEntity, api.do(http.MethodPost, "/webhooks", `{"url": "https://example.com", "events": ["product.sold"]}`).Code)
+	require.Equal(t, http.StatusBadRequest, api.do(http.MethodPost, "/webhooks", `{"url": `).Code)
+
+	response = api.do(http.MethodGet, "/webhooks/"+created.ID, "")
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NotContains(t, response.Body.String(), "secret")
+	response = api.do(http.MethodGet, "/webhooks", "")
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NotContains(t, response.Body.String(), "secret")
+	require.Equal(t, http.StatusNotFound, api.do(http.MethodGet, "/webhooks/missing", "").Code)
+
+	product, err := entity.NewProduct("Product", "description", 10)
+	require.NoError(t, err)
+	delivery := entity.NewWebhookDelivery(created.ID, product.PullEvents()[0])
+	delivery.Failed(0, "connection refused", false, delivery.NextAttemptAt)
+	require.NoError(t, repository.CreateDeliveries(context.Background(), delivery))
+
+	page := deliveries("/webhooks/" + created.ID + "/deliveries")
+	require.Equal(t, 1, page.TotalCount)
+	require.Equal(t, entity.DeliveryDead, page.Deliveries[0].Status)
+	require.Equal(t, 0, deliveries("/webhooks/"+created.ID+"/deliveries?status=pending").TotalCount)
+	require.Equal(t, http.StatusBadRequest, api.do(http.MethodGet, "/webhooks/"+created.ID+"/deliveries?status=lost", "").Code)
+	require.Equal(t, http.StatusNotFound, api.do(http.MethodGet, "/webhooks/missing/deliveries", "").Code)
+
+	page = deliveries("/webhooks/dead-letters")
+	require.Equal(t, 1, page.TotalCount)
+	require.Equal(t, delivery.ID, page.Deliveries[0].ID)
+	require.Equal(t, "connection refused", page.Deliveries[0].LastError)
+
+	retry := "/webhooks/" + created.ID + "/deliveries/" + delivery.ID + "/retry"
+	response = api.do(http.MethodPost, retry, "")
+	require.Equal(t, http.StatusOK, response.Code)
+	var retried usecase.WebhookDeliveryOutputDTO
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &retried))
+	require.Equal(t, entity.DeliveryPending, retried.Status)
+	require.Equal(t, http.StatusConflict, api.do(http.MethodPost, retry, "").Code)
+	require.Equal(t, 0, deliveries("/webhooks/dead-letters").TotalCount)
+
+	require.Equal(t, http.StatusNoContent, api.do(http.MethodDelete, "/webhooks/"+created.ID, "").Code)
+	require.Equal(t, http.StatusNotFound, api.do(http.MethodDelete, "/webhooks/"+created.ID, "").Code)
+}
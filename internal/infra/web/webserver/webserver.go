@@ -1,43 +1,156 @@
 package webserver
 
 import (
+	"context"
+	"errors"
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/infra/logging"
+	"github.com/HaroldoFV/product-service/internal/infra/metrics"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
 	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
 )
 
+// RequestIDHeader carries the ID correlating the log lines of a request. An incoming value is
+// kept so that a request can be followed across services, and the ID is always echoed back.
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID restricts incoming request IDs to a safe length and character set, so that
+// clients cannot forge log lines through the header.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:/+=-]{1,128}$`)
+
+// ActorHeader identifies who makes the changes requested, as recorded in the audit trail. It is
+// expected to be set by the gateway authenticating the caller.
+const ActorHeader = "X-Actor"
+
+// validActor restricts actors to a safe length and character set, which includes e-mail addresses.
+var validActor = regexp.MustCompile(`^[A-Za-z0-9._:/+=@-]{1,128}$`)
+
 type WebServer struct {
 	Router        chi.Router
 	Handlers      map[string]map[string]http.HandlerFunc
 	WebServerPort string
 	BasePath      string
+	// RequestTimeout bounds the context of every request but those of streaming routes. Zero means
+	// no deadline.
+	RequestTimeout time.Duration
+	// ReadTimeout, WriteTimeout and IdleTimeout configure the underlying http.Server. Streaming
+	// routes extend their write deadline as they go. Zero means no timeout.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// HTTPMetrics, when set, instruments every registered route.
+	HTTPMetrics *metrics.HTTPMetrics
+	// Logger writes the access log and is made available to handlers through the request context.
+	Logger *slog.Logger
+
+	server *http.Server
+	mount  sync.Once
+	// streaming holds the routes added by AddStreamingHandler, by method and full path.
+	streaming map[string]bool
 }
 
-func NewWebServer(serverPort string) *WebServer {
+func NewWebServer(serverPort string, logger *slog.Logger) *WebServer {
+	router := chi.NewRouter()
 	return &WebServer{
-		Router:        chi.NewRouter(),
+		Router:        router,
 		Handlers:      make(map[string]map[string]http.HandlerFunc),
+		streaming:     make(map[string]bool),
 		WebServerPort: serverPort,
 		BasePath:      "/api/v1",
+		Logger:        logger,
+		server: &http.Server{
+			Addr:     serverPort,
+			Handler:  router,
+			ErrorLog: slog.NewLogLogger(logger.Handler(), slog.LevelError),
+		},
 	}
 }
 
 func (s *WebServer) AddHandler(method, path string, handler http.HandlerFunc) {
-	fullPath := "/api/v1" + path
+	s.addHandler(method, apiPath(path), handler)
+}
+
+// apiPath returns the path a route added by AddHandler is served at.
+func apiPath(path string) string {
 	if path == "/docs/*" {
-		fullPath = path
+		return path
 	}
+	return "/api/v1" + path
+}
+
+// AddStreamingHandler registers handler like AddHandler, for a route that streams its response
+// for as long as it takes, such as an export. RequestTimeout does not apply to it, and handler is
+// expected to extend the write deadline of the connection as it writes, so that WriteTimeout does
+// not cut the response off either.
+func (s *WebServer) AddStreamingHandler(method, path string, handler http.HandlerFunc) {
+	s.AddHandler(method, path, handler)
+	s.streaming[method+" "+apiPath(path)] = true
+}
+
+// AddRootHandler registers handler at path as is, outside BasePath. It is meant for
+// infrastructure endpoints such as health checks that must not be versioned with the API.
+func (s *WebServer) AddRootHandler(method, path string, handler http.HandlerFunc) {
+	s.addHandler(method, path, handler)
+}
+
+func (s *WebServer) addHandler(method, fullPath string, handler http.HandlerFunc) {
 	if s.Handlers[fullPath] == nil {
 		s.Handlers[fullPath] = make(map[string]http.HandlerFunc)
 	}
 	s.Handlers[fullPath][method] = handler
 }
 
+// Handler mounts the middleware and the registered handlers on Router, the first time it is
+// called, and returns it. Handlers must all be added before. Start serves it; tests can call it
+// to serve the routes of the service without listening on a port.
+func (s *WebServer) Handler() http.Handler {
+	s.mount.Do(s.mountHandlers)
+	return s.Router
+}
+
 func (s *WebServer) Start() error {
-	s.Router.Use(middleware.Logger)
+	s.Handler()
+
+	s.server.ReadTimeout = s.ReadTimeout
+	s.server.ReadHeaderTimeout = s.ReadTimeout
+	s.server.WriteTimeout = s.WriteTimeout
+	s.server.IdleTimeout = s.IdleTimeout
+
+	err := s.server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (s *WebServer) mountHandlers() {
+	// Requests are traced with the global tracer provider, continuing the trace of the caller
+	// when it sends a traceparent header. Infrastructure endpoints outside BasePath, such as
+	// probes and metrics scrapes, are not traced.
+	s.Router.Use(otelhttp.NewMiddleware("http.server", otelhttp.WithFilter(func(r *http.Request) bool {
+		return strings.HasPrefix(r.URL.Path, s.BasePath)
+	})))
+	s.Router.Use(requestID(s.Logger), accessLog(s.Logger), actor(s.Logger))
 
 	for path, methodHandlers := range s.Handlers {
 		for method, handler := range methodHandlers {
+			if s.RequestTimeout > 0 && !s.streaming[method+" "+path] {
+				handler = requestTimeout(s.RequestTimeout)(handler).ServeHTTP
+			}
+			if s.HTTPMetrics != nil {
+				handler = s.HTTPMetrics.Instrument(path, method, handler).ServeHTTP
+			}
+			handler = nameSpan(path, method, handler)
 			switch method {
 			case http.MethodPost:
 				s.Router.Post(path, handler)
@@ -45,10 +158,104 @@ func (s *WebServer) Start() error {
 				s.Router.Get(path, handler)
 			case http.MethodPut:
 				s.Router.Put(path, handler)
+			case http.MethodPatch:
+				s.Router.Patch(path, handler)
 			case http.MethodDelete:
 				s.Router.Delete(path, handler)
 			}
 		}
 	}
-	return http.ListenAndServe(s.WebServerPort, s.Router)
+}
+
+// Shutdown stops accepting connections and waits for in-flight requests to finish, or for ctx
+// to be done. Start returns nil once the shutdown has begun.
+func (s *WebServer) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// requestTimeout cancels the request context once timeout elapses. Unlike middleware.Timeout it
+// does not write a response itself: handlers see the expired context and report it as a 504.
+func requestTimeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// nameSpan names the span of the request after the route it matched, as the path is only
+// known once the router has run.
+func nameSpan(route, method string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route))
+		next(w, r)
+	}
+}
+
+// requestID stores the ID of the request, taken from the X-Request-ID header or generated, in
+// its context along with logger, so that every line logged while serving it can be correlated.
+func requestID(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID.MatchString(id) {
+				id = uuid.NewString()
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			ctx := logging.WithRequestID(r.Context(), id)
+			ctx = logging.NewContext(ctx, logger)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// actor stores the actor sent in the X-Actor header in the request context. Requests without a
+// valid actor are made by domain.AnonymousActor.
+func actor(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name := r.Header.Get(ActorHeader)
+			if !validActor.MatchString(name) {
+				if name != "" {
+					logger.WarnContext(r.Context(), "ignoring invalid actor header", "header", ActorHeader)
+				}
+				name = domain.AnonymousActor
+			}
+			next.ServeHTTP(w, r.WithContext(domain.WithActor(r.Context(), name)))
+		})
+	}
+}
+
+// accessLog logs every request once it has been served. Server errors are logged at the error
+// level, everything else at info.
+func accessLog(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			logger.LogAttrs(r.Context(), level, "request served",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", status),
+				slog.Int("bytes", ww.BytesWritten()),
+				slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+				slog.String("remote_addr", r.RemoteAddr),
+			)
+		})
+	}
 }
@@ -0,0 +1,304 @@
+package webserver_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/infra/logging"
+	"github.com/HaroldoFV/product-service/internal/infra/web/webserver"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func freeAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func TestWebServerShutdownDrainsInFlightRequests(t *testing.T) {
+	address := freeAddress(t)
+	server := webserver.NewWebServer(address, discardLogger)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server.AddHandler(http.MethodGet, "/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Start()
+	}()
+
+	responseStatus := make(chan int, 1)
+	go func() {
+		var resp *http.Response
+		var err error
+		// Retry until the server is listening
+		for i := 0; i < 50; i++ {
+			resp, err = http.Get("http://" + address + "/api/v1/slow")
+			if err == nil {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		if err != nil {
+			responseStatus <- 0
+			return
+		}
+		resp.Body.Close()
+		responseStatus <- resp.StatusCode
+	}()
+	<-started
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		shutdownErr <- server.Shutdown(context.Background())
+	}()
+
+	// Shutdown waits for the request in flight
+	select {
+	case <-shutdownErr:
+		t.Fatal("shutdown returned before the in-flight request finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	require.Equal(t, http.StatusOK, <-responseStatus)
+	require.NoError(t, <-shutdownErr)
+	require.NoError(t, <-serverErr)
+}
+
+func TestWebServerShutdownGracePeriod(t *testing.T) {
+	address := freeAddress(t)
+	server := webserver.NewWebServer(address, discardLogger)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	server.AddHandler(http.MethodGet, "/stuck", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+
+	go server.Start()
+	go func() {
+		for i := 0; i < 50; i++ {
+			resp, err := http.Get("http://" + address + "/api/v1/stuck")
+			if err == nil {
+				resp.Body.Close()
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, server.Shutdown(ctx), context.DeadlineExceeded)
+}
+
+// syncBuffer is a bytes.Buffer safe to write from the server while the test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWebServerRequestID(t *testing.T) {
+	address := freeAddress(t)
+	var logs syncBuffer
+	logger, err := logging.NewLogger(&logs, logging.FormatText, "info")
+	require.NoError(t, err)
+	server := webserver.NewWebServer(address, logger)
+
+	server.AddHandler(http.MethodGet, "/echo", func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).InfoContext(r.Context(), "handler called")
+		w.Write([]byte(logging.RequestIDFromContext(r.Context())))
+	})
+	go server.Start()
+	defer server.Shutdown(context.Background())
+
+	get := func(requestID string) *http.Response {
+		request, err := http.NewRequest(http.MethodGet, "http://"+address+"/api/v1/echo", nil)
+		require.NoError(t, err)
+		if requestID != "" {
+			request.Header.Set(webserver.RequestIDHeader, requestID)
+		}
+		var resp *http.Response
+		for i := 0; i < 50; i++ {
+			resp, err = http.DefaultClient.Do(request)
+			if err == nil {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Incoming ID is kept", func(t *testing.T) {
+		resp := get("trace-42")
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		require.Equal(t, "trace-42", resp.Header.Get(webserver.RequestIDHeader))
+		require.Equal(t, "trace-42", string(body))
+		require.Eventually(t, func() bool {
+			return strings.Contains(logs.String(), "msg=\"handler called\" request_id=trace-42") &&
+				strings.Contains(logs.String(), "msg=\"request served\" method=GET path=/api/v1/echo status=200")
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("ID is generated", func(t *testing.T) {
+		resp := get("")
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		require.Len(t, resp.Header.Get(webserver.RequestIDHeader), 36)
+		require.Equal(t, resp.Header.Get(webserver.RequestIDHeader), string(body))
+	})
+
+	t.Run("Invalid incoming ID is replaced", func(t *testing.T) {
+		resp := get("forged id\" level=ERROR")
+		defer resp.Body.Close()
+		require.Len(t, resp.Header.Get(webserver.RequestIDHeader), 36)
+		require.NotContains(t, logs.String(), "forged")
+	})
+}
+
+func TestWebServerRequestTimeout(t *testing.T) {
+	server := webserver.NewWebServer("", discardLogger)
+	server.RequestTimeout = time.Minute
+	deadline := func(w http.ResponseWriter, r *http.Request) {
+		_, ok := r.Context().Deadline()
+		fmt.Fprint(w, ok)
+	}
+	server.AddHandler(http.MethodGet, "/products", deadline)
+	server.AddStreamingHandler(http.MethodGet, "/products/export", deadline)
+
+	for path, expected := range map[string]string{"/api/v1/products": "true", "/api/v1/products/export": "false"} {
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, expected, recorder.Body.String(), "deadline of %s", path)
+	}
+}
+
+func TestWebServerActor(t *testing.T) {
+	address := freeAddress(t)
+	var logs syncBuffer
+	logger, err := logging.NewLogger(&logs, logging.FormatText, "info")
+	require.NoError(t, err)
+	server := webserver.NewWebServer(address, logger)
+
+	server.AddHandler(http.MethodGet, "/actor", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(domain.ActorFromContext(r.Context())))
+	})
+	go server.Start()
+	defer server.Shutdown(context.Background())
+
+	get := func(actor string) string {
+		request, err := http.NewRequest(http.MethodGet, "http://"+address+"/api/v1/actor", nil)
+		require.NoError(t, err)
+		if actor != "" {
+			request.Header.Set(webserver.ActorHeader, actor)
+		}
+		var resp *http.Response
+		for i := 0; i < 50; i++ {
+			resp, err = http.DefaultClient.Do(request)
+			if err == nil {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	require.Equal(t, "alice@example.com", get("alice@example.com"))
+	require.Equal(t, domain.AnonymousActor, get(""))
+	require.Equal(t, domain.AnonymousActor, get("alice\" level=ERROR"))
+	require.Equal(t, domain.AnonymousActor, get(strings.Repeat("a", 129)))
+	require.Contains(t, logs.String(), "ignoring invalid actor header")
+	require.NotContains(t, logs.String(), "level=ERROR")
+}
+
+func TestWebServerTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	}()
+
+	address := freeAddress(t)
+	server := webserver.NewWebServer(address, discardLogger)
+	server.AddHandler(http.MethodGet, "/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server.AddRootHandler(http.MethodGet, "/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	go server.Start()
+	defer server.Shutdown(context.Background())
+
+	get := func(path string) {
+		request, err := http.NewRequest(http.MethodGet, "http://"+address+path, nil)
+		require.NoError(t, err)
+		request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		var resp *http.Response
+		for i := 0; i < 50; i++ {
+			resp, err = http.DefaultClient.Do(request)
+			if err == nil {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	get("/healthz")
+	get("/api/v1/products/42")
+
+	require.Eventually(t, func() bool { return len(recorder.Ended()) > 0 }, time.Second, 10*time.Millisecond)
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "GET /api/v1/products/{id}", spans[0].Name())
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	require.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+}
@@ -0,0 +1,104 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/events"
+)
+
+// Headers sent with every delivery.
+const (
+	DeliveryHeader  = "X-Webhook-Delivery"
+	EventHeader     = "X-Webhook-Event"
+	TimestampHeader = "X-Webhook-Timestamp"
+	// SignatureHeader holds "sha256=" followed by the hex encoded HMAC-SHA256, keyed with the
+	// secret of the webhook, of the timestamp, a dot and the body.
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// maxResponseSize bounds the part of the response body read, only to reuse the connection.
+const maxResponseSize = 64 * 1024
+
+// HTTPSender implements domain.WebhookSender, posting the JSON representation of the event.
+type HTTPSender struct {
+	Client *http.Client
+}
+
+// NewHTTPSender returns a sender giving up on receivers that take longer than timeout to answer.
+// Redirects are not followed: a webhook must be registered with its final URL.
+func NewHTTPSender(timeout time.Duration) *HTTPSender {
+	return &HTTPSender{Client: &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+func (s *HTTPSender) Send(ctx context.Context, webhook *entity.Webhook, delivery *entity.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(events.NewMessage(&delivery.Event))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "product-service-webhooks")
+	request.Header.Set(DeliveryHeader, delivery.ID)
+	request.Header.Set(EventHeader, delivery.Event.Type)
+	request.Header.Set(TimestampHeader, timestamp)
+	request.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, body))
+
+	response, err := s.Client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, maxResponseSize))
+	return response.StatusCode, nil
+}
+
+// Sign returns the value of SignatureHeader for body sent at timestamp, in Unix seconds.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a delivery received at now, rejecting deliveries signed more
+// than tolerance ago so that captured requests cannot be replayed. Receivers written in Go can
+// use it as is.
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration, now time.Time) error {
+	timestamp := header.Get(TimestampHeader)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s header", TimestampHeader)
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("delivery signed %s ago, outside the tolerance", age.Round(time.Second))
+	}
+	signature := header.Get(SignatureHeader)
+	if !strings.HasPrefix(signature, "sha256=") || !hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body))) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
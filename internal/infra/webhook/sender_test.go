@@ -0,0 +1,103 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/events"
+	"github.com/HaroldoFV/product-service/internal/infra/webhook"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "0123456789abcdef"
+
+func newDelivery(t *testing.T, url string) (*entity.Webhook, *entity.WebhookDelivery) {
+	t.Helper()
+	target, err := entity.NewWebhook(url, nil, secret)
+	require.NoError(t, err)
+	product, err := entity.NewProduct("Product", "description", 10)
+	require.NoError(t, err)
+	return target, entity.NewWebhookDelivery(target.ID, product.PullEvents()[0])
+}
+
+func TestHTTPSender(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		received <- r
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	target, delivery := newDelivery(t, server.URL)
+	statusCode, err := webhook.NewHTTPSender(time.Second).Send(context.Background(), target, delivery)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusAccepted, statusCode)
+
+	request := <-received
+	require.Equal(t, http.MethodPost, request.Method)
+	require.Equal(t, "application/json", request.Header.Get("Content-Type"))
+	require.Equal(t, delivery.ID, request.Header.Get(webhook.DeliveryHeader))
+	require.Equal(t, entity.ProductCreated, request.Header.Get(webhook.EventHeader))
+	require.NoError(t, webhook.Verify(secret, request.Header, body, time.Minute, time.Now()))
+
+	var message events.Message
+	require.NoError(t, json.Unmarshal(body, &message))
+	require.Equal(t, delivery.Event.ID, message.ID)
+	require.Equal(t, delivery.Event.ProductID, message.ProductID)
+}
+
+func TestHTTPSenderDoesNotFollowRedirects(t *testing.T) {
+	redirected := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			redirected = true
+			return
+		}
+		http.Redirect(w, r, "/moved", http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	target, delivery := newDelivery(t, server.URL)
+	statusCode, err := webhook.NewHTTPSender(time.Second).Send(context.Background(), target, delivery)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTemporaryRedirect, statusCode)
+	require.False(t, redirected)
+}
+
+func TestHTTPSenderTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	target, delivery := newDelivery(t, server.URL)
+	statusCode, err := webhook.NewHTTPSender(50*time.Millisecond).Send(context.Background(), target, delivery)
+	require.Error(t, err)
+	require.Zero(t, statusCode)
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	now := time.Unix(1700000000, 0)
+	header := http.Header{}
+	header.Set(webhook.TimestampHeader, "1700000000")
+	header.Set(webhook.SignatureHeader, webhook.Sign(secret, "1700000000", body))
+
+	require.NoError(t, webhook.Verify(secret, header, body, 5*time.Minute, now))
+	require.ErrorContains(t, webhook.Verify("another secret!!", header, body, 5*time.Minute, now), "invalid signature")
+	require.ErrorContains(t, webhook.Verify(secret, header, []byte(`{"id":"2"}`), 5*time.Minute, now), "invalid signature")
+	require.ErrorContains(t, webhook.Verify(secret, header, body, 5*time.Minute, now.Add(10*time.Minute)), "outside the tolerance")
+
+	header.Set(webhook.TimestampHeader, "yesterday")
+	require.ErrorContains(t, webhook.Verify(secret, header, body, 5*time.Minute, now), "invalid X-Webhook-Timestamp header")
+}
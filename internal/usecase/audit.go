@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+)
+
+// newAuditEntry records operation on product, made by the actor in ctx. product is the product
+// once changed, or as last stored when it was removed.
+func newAuditEntry(ctx context.Context, operation string, product *entity.Product, changes []entity.FieldChange) *entity.ProductAuditEntry {
+	return entity.NewProductAuditEntry(product.GetID(), domain.ActorFromContext(ctx), operation, product.GetVersion(), changes)
+}
+
+// trashChange is the change recorded when a product is moved to or taken out of the trash.
+func trashChange(deleted bool) []entity.FieldChange {
+	return []entity.FieldChange{{Field: entity.AuditFieldDeleted, Before: !deleted, After: deleted}}
+}
+
+// pullEvents returns the events raised by products, in order.
+func pullEvents(products ...*entity.Product) []*entity.ProductEvent {
+	var events []*entity.ProductEvent
+	for _, product := range products {
+		events = append(events, product.PullEvents()...)
+	}
+	return events
+}
@@ -1,39 +1,63 @@
 package usecase
 
 import (
+	"context"
+	"log/slog"
+
 	"github.com/HaroldoFV/product-service/internal/domain"
 	"github.com/HaroldoFV/product-service/internal/domain/entity"
 )
 
 type CreateProductUseCase struct {
-	ProductRepository domain.ProductRepositoryInterface
+	ProductRepository  domain.ProductRepositoryInterface
+	AuditRepository    domain.ProductAuditRepositoryInterface
+	OutboxRepository   domain.OutboxRepositoryInterface
+	TransactionManager domain.TransactionManager
+	Logger             *slog.Logger
 }
 
 func NewCreateProductUseCase(
 	productRepository domain.ProductRepositoryInterface,
+	auditRepository domain.ProductAuditRepositoryInterface,
+	outboxRepository domain.OutboxRepositoryInterface,
+	transactionManager domain.TransactionManager,
+	logger *slog.Logger,
 ) *CreateProductUseCase {
 	return &CreateProductUseCase{
-		ProductRepository: productRepository,
+		ProductRepository:  productRepository,
+		AuditRepository:    auditRepository,
+		OutboxRepository:   outboxRepository,
+		TransactionManager: transactionManager,
+		Logger:             logger,
 	}
 }
 
-func (c *CreateProductUseCase) Execute(input ProductInputDTO) (ProductOutputDTO, error) {
-	product, _ := entity.NewProduct(
+func (c *CreateProductUseCase) Execute(ctx context.Context, input ProductInputDTO) (_ ProductOutputDTO, err error) {
+	ctx, span := tracer.Start(ctx, "CreateProductUseCase.Execute")
+	defer func() { endSpan(span, err) }()
+
+	product, err := entity.NewProduct(
 		input.Name,
 		input.Description,
 		input.Price,
 	)
-
-	if err := c.ProductRepository.Create(product); err != nil {
+	if err != nil {
 		return ProductOutputDTO{}, err
 	}
-	dto := ProductOutputDTO{
-		ID:          product.GetID(),
-		Name:        product.GetName(),
-		Description: product.GetDescription(),
-		Price:       product.GetPrice(),
-		Status:      product.GetStatus(),
+
+	err = c.TransactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := c.ProductRepository.Create(ctx, product); err != nil {
+			return err
+		}
+		if err := c.AuditRepository.Append(ctx, newAuditEntry(ctx, entity.AuditCreate, product, entity.DiffProducts(nil, product))); err != nil {
+			return err
+		}
+		return c.OutboxRepository.Append(ctx, product.PullEvents()...)
+	})
+	if err != nil {
+		return ProductOutputDTO{}, err
 	}
+	c.Logger.InfoContext(ctx, "product created", "product_id", product.GetID())
 
-	return dto, nil
+	return newProductOutputDTO(product), nil
 }
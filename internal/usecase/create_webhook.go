@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+)
+
+type CreateWebhookUseCase struct {
+	WebhookRepository domain.WebhookRepositoryInterface
+	Logger            *slog.Logger
+}
+
+func NewCreateWebhookUseCase(webhookRepository domain.WebhookRepositoryInterface, logger *slog.Logger) *CreateWebhookUseCase {
+	return &CreateWebhookUseCase{
+		WebhookRepository: webhookRepository,
+		Logger:            logger,
+	}
+}
+
+// Execute subscribes input.URL to product events. The secret, generated when not given, is only
+// returned here.
+func (u *CreateWebhookUseCase) Execute(ctx context.Context, input WebhookInputDTO) (_ WebhookOutputDTO, err error) {
+	ctx, span := tracer.Start(ctx, "CreateWebhookUseCase.Execute")
+	defer func() { endSpan(span, err) }()
+
+	secret := input.Secret
+	if secret == "" {
+		random := make([]byte, 32)
+		if _, err = rand.Read(random); err != nil {
+			return WebhookOutputDTO{}, err
+		}
+		secret = hex.EncodeToString(random)
+	}
+
+	webhook, err := entity.NewWebhook(input.URL, input.Events, secret)
+	if err != nil {
+		return WebhookOutputDTO{}, err
+	}
+	if err = u.WebhookRepository.Create(ctx, webhook); err != nil {
+		return WebhookOutputDTO{}, err
+	}
+	u.Logger.InfoContext(ctx, "webhook created", "webhook_id", webhook.ID, "events", webhook.Events)
+
+	output := newWebhookOutputDTO(webhook)
+	output.Secret = webhook.Secret
+	return output, nil
+}
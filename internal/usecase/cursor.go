@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+)
+
+// CursorCodec turns product cursors into opaque tokens signed with HMAC-SHA256, so
+// clients can neither forge positions nor tamper with the sort a cursor was created for.
+type CursorCodec struct {
+	secret []byte
+}
+
+func NewCursorCodec(secret []byte) *CursorCodec {
+	return &CursorCodec{secret: secret}
+}
+
+// cursorPayload is the serialized form of domain.ProductCursor.
+type cursorPayload struct {
+	Sort     string   `json:"s"`
+	Values   []string `json:"v"`
+	Backward bool     `json:"b,omitempty"`
+}
+
+func (c *CursorCodec) Encode(cursor domain.ProductCursor) string {
+	payload, _ := json.Marshal(cursorPayload{
+		Sort:     cursor.Sort.String(),
+		Values:   cursor.Values,
+		Backward: cursor.Backward,
+	})
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(c.sign(payload))
+}
+
+func (c *CursorCodec) Decode(token string) (domain.ProductCursor, error) {
+	invalid := fmt.Errorf("%w: invalid cursor", entity.ErrInvalidInput)
+
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return domain.ProductCursor{}, invalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return domain.ProductCursor{}, invalid
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, c.sign(payload)) {
+		return domain.ProductCursor{}, invalid
+	}
+
+	var decoded cursorPayload
+	if err = json.Unmarshal(payload, &decoded); err != nil {
+		return domain.ProductCursor{}, invalid
+	}
+	sort, err := domain.ParseProductSort(decoded.Sort)
+	if err != nil || len(decoded.Values) != len(sort.WithTiebreaker()) {
+		return domain.ProductCursor{}, invalid
+	}
+	return domain.ProductCursor{
+		Sort:     sort,
+		Values:   decoded.Values,
+		Backward: decoded.Backward,
+	}, nil
+}
+
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -1,25 +1,72 @@
 package usecase
 
 import (
+	"context"
+	"log/slog"
+
 	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type DeleteProductUseCase struct {
-	ProductRepository domain.ProductRepositoryInterface
+	ProductRepository  domain.ProductRepositoryInterface
+	AuditRepository    domain.ProductAuditRepositoryInterface
+	OutboxRepository   domain.OutboxRepositoryInterface
+	TransactionManager domain.TransactionManager
+	Logger             *slog.Logger
 }
 
 func NewDeleteProductUseCase(
 	productRepository domain.ProductRepositoryInterface,
+	auditRepository domain.ProductAuditRepositoryInterface,
+	outboxRepository domain.OutboxRepositoryInterface,
+	transactionManager domain.TransactionManager,
+	logger *slog.Logger,
 ) *DeleteProductUseCase {
 	return &DeleteProductUseCase{
-		ProductRepository: productRepository,
+		ProductRepository:  productRepository,
+		AuditRepository:    auditRepository,
+		OutboxRepository:   outboxRepository,
+		TransactionManager: transactionManager,
+		Logger:             logger,
 	}
 }
 
-func (u *DeleteProductUseCase) Execute(id string) error {
-	err := u.ProductRepository.Delete(id)
+// Execute moves the product to the trash, from where it can be restored until purged. With
+// permanent set the product is removed for good instead, even if it is already in the trash.
+func (u *DeleteProductUseCase) Execute(ctx context.Context, id string, permanent bool) (err error) {
+	ctx, span := tracer.Start(ctx, "DeleteProductUseCase.Execute", trace.WithAttributes(
+		attribute.String("product.id", id), attribute.Bool("permanent", permanent)))
+	defer func() { endSpan(span, err) }()
+
+	err = u.TransactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if permanent {
+			product, err := u.ProductRepository.DeletePermanently(ctx, id)
+			if err != nil {
+				return err
+			}
+			product.RecordDeleted(true)
+			if err := u.AuditRepository.Append(ctx, newAuditEntry(ctx, entity.AuditDeletePermanently, product, entity.DiffProducts(product, nil))); err != nil {
+				return err
+			}
+			return u.OutboxRepository.Append(ctx, product.PullEvents()...)
+		}
+
+		product, err := u.ProductRepository.Delete(ctx, id)
+		if err != nil {
+			return err
+		}
+		product.RecordDeleted(false)
+		if err := u.AuditRepository.Append(ctx, newAuditEntry(ctx, entity.AuditDelete, product, trashChange(true))); err != nil {
+			return err
+		}
+		return u.OutboxRepository.Append(ctx, product.PullEvents()...)
+	})
 	if err != nil {
 		return err
 	}
+	u.Logger.InfoContext(ctx, "product deleted", "product_id", id, "permanent", permanent)
 	return nil
 }
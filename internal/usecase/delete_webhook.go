@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type DeleteWebhookUseCase struct {
+	WebhookRepository domain.WebhookRepositoryInterface
+	Logger            *slog.Logger
+}
+
+func NewDeleteWebhookUseCase(webhookRepository domain.WebhookRepositoryInterface, logger *slog.Logger) *DeleteWebhookUseCase {
+	return &DeleteWebhookUseCase{
+		WebhookRepository: webhookRepository,
+		Logger:            logger,
+	}
+}
+
+// Execute removes the webhook along with its deliveries, including the pending ones.
+func (u *DeleteWebhookUseCase) Execute(ctx context.Context, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "DeleteWebhookUseCase.Execute", trace.WithAttributes(attribute.String("webhook.id", id)))
+	defer func() { endSpan(span, err) }()
+
+	if err = u.WebhookRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	u.Logger.InfoContext(ctx, "webhook deleted", "webhook_id", id)
+	return nil
+}
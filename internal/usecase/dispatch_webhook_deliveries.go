@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WebhookRetryPolicy tells how many times and how often a delivery is attempted.
+type WebhookRetryPolicy struct {
+	// MaxAttempts is the number of attempts after which a failing delivery becomes dead.
+	MaxAttempts int
+	// BaseDelay is the wait before the second attempt, doubled before every following one up to
+	// MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// Delay returns the wait before the attempt following the given number of failed attempts.
+func (p WebhookRetryPolicy) Delay(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+type DispatchWebhookDeliveriesUseCase struct {
+	WebhookRepository domain.WebhookRepositoryInterface
+	Sender            domain.WebhookSender
+	RetryPolicy       WebhookRetryPolicy
+	// Lease keeps claimed deliveries from being claimed again while they are sent. It must be
+	// longer than the time taken to send a delivery.
+	Lease  time.Duration
+	Logger *slog.Logger
+}
+
+func NewDispatchWebhookDeliveriesUseCase(
+	webhookRepository domain.WebhookRepositoryInterface,
+	sender domain.WebhookSender,
+	retryPolicy WebhookRetryPolicy,
+	lease time.Duration,
+	logger *slog.Logger,
+) *DispatchWebhookDeliveriesUseCase {
+	return &DispatchWebhookDeliveriesUseCase{
+		WebhookRepository: webhookRepository,
+		Sender:            sender,
+		RetryPolicy:       retryPolicy,
+		Lease:             lease,
+		Logger:            logger,
+	}
+}
+
+// Execute sends up to limit due deliveries, concurrently, and returns how many were attempted. A
+// delivery answered with a 2xx status is delivered. Any other outcome is retried with an
+// exponential backoff until the attempts of the retry policy are exhausted, when the delivery
+// becomes dead. Deliveries may therefore reach a webhook more than once and out of order.
+func (u *DispatchWebhookDeliveriesUseCase) Execute(ctx context.Context, limit int) (_ int, err error) {
+	ctx, span := tracer.Start(ctx, "DispatchWebhookDeliveriesUseCase.Execute", trace.WithAttributes(attribute.Int("limit", limit)))
+	defer func() { endSpan(span, err) }()
+
+	if limit <= 0 {
+		return 0, fmt.Errorf("%w: limit must be greater than zero", entity.ErrInvalidInput)
+	}
+
+	deliveries, err := u.WebhookRepository.ClaimDueDeliveries(ctx, time.Now().UTC(), u.Lease, limit)
+	if err != nil || len(deliveries) == 0 {
+		return 0, err
+	}
+	span.SetAttributes(attribute.Int("deliveries", len(deliveries)))
+
+	webhooks := make(map[string]*entity.Webhook)
+	for _, delivery := range deliveries {
+		if _, ok := webhooks[delivery.WebhookID]; ok {
+			continue
+		}
+		webhook, err := u.WebhookRepository.GetByID(ctx, delivery.WebhookID)
+		if errors.Is(err, entity.ErrNotFound) {
+			// Deleted since the deliveries were claimed, along with them
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		webhooks[delivery.WebhookID] = webhook
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(deliveries))
+	for i, delivery := range deliveries {
+		webhook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = u.send(ctx, webhook, delivery)
+		}()
+	}
+	wg.Wait()
+
+	return len(deliveries), errors.Join(errs...)
+}
+
+// send makes an attempt to deliver delivery and records its outcome. When ctx ends first nothing
+// is recorded: the delivery is claimed again once its lease expires.
+func (u *DispatchWebhookDeliveriesUseCase) send(ctx context.Context, webhook *entity.Webhook, delivery *entity.WebhookDelivery) error {
+	statusCode, err := u.Sender.Send(ctx, webhook, delivery)
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	logger := u.Logger.With("webhook_id", webhook.ID, "delivery_id", delivery.ID, "event_type", delivery.Event.Type)
+	switch {
+	case err == nil && statusCode >= 200 && statusCode < 300:
+		delivery.Succeeded(statusCode, now)
+		logger.DebugContext(ctx, "webhook delivered", "status", statusCode)
+	default:
+		reason := fmt.Sprintf("unexpected status %d", statusCode)
+		if err != nil {
+			reason = err.Error()
+		}
+		retry := delivery.Attempts+1 < u.RetryPolicy.MaxAttempts
+		delivery.Failed(statusCode, reason, retry, now.Add(u.RetryPolicy.Delay(delivery.Attempts+1)))
+		if retry {
+			logger.WarnContext(ctx, "webhook delivery failed, will retry",
+				"attempts", delivery.Attempts, "next_attempt_at", delivery.NextAttemptAt, "error", reason)
+		} else {
+			logger.ErrorContext(ctx, "webhook delivery failed, moved to the dead letters",
+				"attempts", delivery.Attempts, "error", reason)
+		}
+	}
+
+	err = u.WebhookRepository.UpdateDelivery(ctx, delivery)
+	if errors.Is(err, entity.ErrNotFound) {
+		return nil
+	}
+	return err
+}
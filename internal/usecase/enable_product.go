@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type EnableProductUseCase struct {
+	ProductRepository  domain.ProductRepositoryInterface
+	AuditRepository    domain.ProductAuditRepositoryInterface
+	OutboxRepository   domain.OutboxRepositoryInterface
+	TransactionManager domain.TransactionManager
+	Logger             *slog.Logger
+}
+
+func NewEnableProductUseCase(
+	productRepository domain.ProductRepositoryInterface,
+	auditRepository domain.ProductAuditRepositoryInterface,
+	outboxRepository domain.OutboxRepositoryInterface,
+	transactionManager domain.TransactionManager,
+	logger *slog.Logger,
+) *EnableProductUseCase {
+	return &EnableProductUseCase{
+		ProductRepository:  productRepository,
+		AuditRepository:    auditRepository,
+		OutboxRepository:   outboxRepository,
+		TransactionManager: transactionManager,
+		Logger:             logger,
+	}
+}
+
+func (u *EnableProductUseCase) Execute(ctx context.Context, id string) (_ ProductOutputDTO, err error) {
+	ctx, span := tracer.Start(ctx, "EnableProductUseCase.Execute", trace.WithAttributes(attribute.String("product.id", id)))
+	defer func() { endSpan(span, err) }()
+
+	product, err := u.ProductRepository.GetByID(ctx, id)
+	if err != nil {
+		return ProductOutputDTO{}, err
+	}
+
+	before := *product
+	err = product.Enable()
+	if err != nil {
+		return ProductOutputDTO{}, err
+	}
+
+	err = u.TransactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.ProductRepository.Update(ctx, product); err != nil {
+			return err
+		}
+		if err := u.AuditRepository.Append(ctx, newAuditEntry(ctx, entity.AuditEnable, product, entity.DiffProducts(&before, product))); err != nil {
+			return err
+		}
+		return u.OutboxRepository.Append(ctx, product.PullEvents()...)
+	})
+	if err != nil {
+		return ProductOutputDTO{}, err
+	}
+	u.Logger.InfoContext(ctx, "product enabled", "product_id", product.GetID())
+
+	return newProductOutputDTO(product), nil
+}
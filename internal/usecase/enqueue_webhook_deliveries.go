@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type EnqueueWebhookDeliveriesUseCase struct {
+	WebhookRepository  domain.WebhookRepositoryInterface
+	TransactionManager domain.TransactionManager
+}
+
+func NewEnqueueWebhookDeliveriesUseCase(
+	webhookRepository domain.WebhookRepositoryInterface,
+	transactionManager domain.TransactionManager,
+) *EnqueueWebhookDeliveriesUseCase {
+	return &EnqueueWebhookDeliveriesUseCase{
+		WebhookRepository:  webhookRepository,
+		TransactionManager: transactionManager,
+	}
+}
+
+// Execute creates a delivery of event for every webhook subscribed to its type, to be sent by
+// DispatchWebhookDeliveriesUseCase. It is run by the outbox relay as one of its publishers, in a
+// transaction of its own: the deliveries are created for every webhook or none, and a failure
+// leaves the outbox free to record the failed attempt.
+func (u *EnqueueWebhookDeliveriesUseCase) Execute(ctx context.Context, event *entity.ProductEvent) (err error) {
+	ctx, span := tracer.Start(ctx, "EnqueueWebhookDeliveriesUseCase.Execute", trace.WithAttributes(
+		attribute.String("event.id", event.ID), attribute.String("event.type", event.Type)))
+	defer func() { endSpan(span, err) }()
+
+	return u.TransactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		webhooks, err := u.WebhookRepository.List(ctx)
+		if err != nil {
+			return err
+		}
+
+		var deliveries []*entity.WebhookDelivery
+		for _, webhook := range webhooks {
+			if webhook.Matches(event.Type) {
+				deliveries = append(deliveries, entity.NewWebhookDelivery(webhook.ID, event))
+			}
+		}
+		span.SetAttributes(attribute.Int("deliveries", len(deliveries)))
+		return u.WebhookRepository.CreateDeliveries(ctx, deliveries...)
+	})
+}
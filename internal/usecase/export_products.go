@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Formats accepted by ExportProductsUseCase.
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatJSON   = "json"
+)
+
+// exportFlushEvery is the number of rows written between flushes of the output.
+const exportFlushEvery = 100
+
+type ExportProductsUseCase struct {
+	ProductRepository domain.ProductRepositoryInterface
+}
+
+func NewExportProductsUseCase(
+	productRepository domain.ProductRepositoryInterface,
+) *ExportProductsUseCase {
+	return &ExportProductsUseCase{
+		ProductRepository: productRepository,
+	}
+}
+
+// Execute streams the products matching input.Filter to input.Writer one product at a time.
+// Nothing is written before the first product is read, so errors returned before that point
+// can still be reported to the client. If the writer implements Flush() it is flushed periodically.
+func (u *ExportProductsUseCase) Execute(ctx context.Context, input ProductExportInputDTO) (err error) {
+	ctx, span := tracer.Start(ctx, "ExportProductsUseCase.Execute", trace.WithAttributes(attribute.String("export.format", input.Format)))
+	defer func() { endSpan(span, err) }()
+
+	err = input.Filter.Validate()
+	if err != nil {
+		return err
+	}
+
+	encoder, err := newExportEncoder(input.Format, input.Writer)
+	if err != nil {
+		return err
+	}
+	flusher, _ := input.Writer.(interface{ Flush() })
+
+	count := 0
+	err = u.ProductRepository.ForEach(ctx, input.Filter, func(product *entity.Product) error {
+		if err := encoder.Encode(newProductOutputDTO(product)); err != nil {
+			return err
+		}
+		count++
+		if count%exportFlushEvery == 0 {
+			if err := encoder.Flush(); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// exportEncoder writes products in a single output format.
+type exportEncoder interface {
+	Encode(product ProductOutputDTO) error
+	Flush() error
+	// Close writes any trailing data, such as the closing bracket of a JSON array.
+	Close() error
+}
+
+func newExportEncoder(format string, w io.Writer) (exportEncoder, error) {
+	switch format {
+	case ExportFormatCSV:
+		return &csvExportEncoder{writer: csv.NewWriter(w)}, nil
+	case ExportFormatNDJSON:
+		return &ndjsonExportEncoder{encoder: json.NewEncoder(w)}, nil
+	case ExportFormatJSON:
+		return &jsonExportEncoder{writer: w}, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported export format %q", entity.ErrInvalidInput, format)
+	}
+}
+
+var csvExportHeader = []string{"id", "name", "description", "price", "status", "created_at"}
+
+type csvExportEncoder struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (e *csvExportEncoder) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+	return e.writer.Write(csvExportHeader)
+}
+
+func (e *csvExportEncoder) Encode(product ProductOutputDTO) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	return e.writer.Write([]string{
+		product.ID,
+		product.Name,
+		product.Description,
+		strconv.FormatFloat(product.Price, 'f', -1, 64),
+		product.Status,
+		product.CreatedAt.Format(time.RFC3339Nano),
+	})
+}
+
+func (e *csvExportEncoder) Flush() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *csvExportEncoder) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	return e.Flush()
+}
+
+type ndjsonExportEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonExportEncoder) Encode(product ProductOutputDTO) error {
+	return e.encoder.Encode(product)
+}
+
+func (e *ndjsonExportEncoder) Flush() error {
+	return nil
+}
+
+func (e *ndjsonExportEncoder) Close() error {
+	return nil
+}
+
+type jsonExportEncoder struct {
+	writer  io.Writer
+	started bool
+}
+
+func (e *jsonExportEncoder) Encode(product ProductOutputDTO) error {
+	data, err := json.Marshal(product)
+	if err != nil {
+		return err
+	}
+	separator := ","
+	if !e.started {
+		separator = "["
+		e.started = true
+	}
+	if _, err = io.WriteString(e.writer, separator); err != nil {
+		return err
+	}
+	_, err = e.writer.Write(data)
+	return err
+}
+
+func (e *jsonExportEncoder) Flush() error {
+	return nil
+}
+
+func (e *jsonExportEncoder) Close() error {
+	if !e.started {
+		_, err := io.WriteString(e.writer, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(e.writer, "]\n")
+	return err
+}
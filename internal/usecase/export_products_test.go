@@ -0,0 +1,78 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportProductsUseCase(t *testing.T) {
+	repository := database.NewInMemoryProductRepository()
+	enabled, _ := entity.NewProduct("Product A", "Description, with comma", 10.5)
+	require.Nil(t, enabled.Enable())
+	disabled, _ := entity.NewProduct("Product B", "Description B", 20)
+	require.Nil(t, repository.Create(context.Background(), enabled))
+	require.Nil(t, repository.Create(context.Background(), disabled))
+
+	exportProducts := usecase.NewExportProductsUseCase(repository)
+
+	t.Run("Export CSV filtered by status", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := exportProducts.Execute(context.Background(), usecase.ProductExportInputDTO{
+			Format: usecase.ExportFormatCSV,
+			Filter: domain.ProductFilter{Status: entity.ENABLED},
+			Writer: &buf,
+		})
+		require.Nil(t, err)
+		require.Equal(t, "id,name,description,price,status,created_at\n"+
+			enabled.GetID()+`,Product A,"Description, with comma",10.5,enabled,`+
+			enabled.GetCreatedAt().Format(time.RFC3339Nano)+"\n", buf.String())
+	})
+
+	t.Run("Export NDJSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := exportProducts.Execute(context.Background(), usecase.ProductExportInputDTO{Format: usecase.ExportFormatNDJSON, Writer: &buf})
+		require.Nil(t, err)
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+
+		var product usecase.ProductOutputDTO
+		require.Nil(t, json.Unmarshal([]byte(lines[0]), &product))
+		require.NotEmpty(t, product.ID)
+	})
+
+	t.Run("Export JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := exportProducts.Execute(context.Background(), usecase.ProductExportInputDTO{Format: usecase.ExportFormatJSON, Writer: &buf})
+		require.Nil(t, err)
+
+		var products []usecase.ProductOutputDTO
+		require.Nil(t, json.Unmarshal(buf.Bytes(), &products))
+		require.Len(t, products, 2)
+		require.Less(t, products[0].ID, products[1].ID)
+	})
+
+	t.Run("Export empty JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := usecase.NewExportProductsUseCase(database.NewInMemoryProductRepository()).Execute(context.Background(),
+			usecase.ProductExportInputDTO{Format: usecase.ExportFormatJSON, Writer: &buf})
+		require.Nil(t, err)
+		require.Equal(t, "[]\n", buf.String())
+	})
+
+	t.Run("Invalid status", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := exportProducts.Execute(context.Background(), usecase.ProductExportInputDTO{Format: usecase.ExportFormatCSV, Filter: domain.ProductFilter{Status: "archived"}, Writer: &buf})
+		require.ErrorIs(t, err, entity.ErrInvalidInput)
+		require.Empty(t, buf.String())
+	})
+}
@@ -1,7 +1,11 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/HaroldoFV/product-service/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type GetProductUseCase struct {
@@ -14,18 +18,14 @@ func NewGetProductUseCase(productRepository domain.ProductRepositoryInterface) *
 	}
 }
 
-func (l *GetProductUseCase) Execute(id string) (ProductOutputDTO, error) {
-	product, err := l.ProductRepository.GetByID(id)
+func (l *GetProductUseCase) Execute(ctx context.Context, id string) (_ ProductOutputDTO, err error) {
+	ctx, span := tracer.Start(ctx, "GetProductUseCase.Execute", trace.WithAttributes(attribute.String("product.id", id)))
+	defer func() { endSpan(span, err) }()
+
+	product, err := l.ProductRepository.GetByID(ctx, id)
 	if err != nil {
 		return ProductOutputDTO{}, err
 	}
 
-	var outputProduct = ProductOutputDTO{
-		ID:          product.GetID(),
-		Name:        product.GetName(),
-		Description: product.GetDescription(),
-		Price:       product.GetPrice(),
-		Status:      product.GetStatus(),
-	}
-	return outputProduct, nil
+	return newProductOutputDTO(product), nil
 }
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type GetProductHistoryUseCase struct {
+	ProductRepository domain.ProductRepositoryInterface
+	AuditRepository   domain.ProductAuditRepositoryInterface
+}
+
+func NewGetProductHistoryUseCase(
+	productRepository domain.ProductRepositoryInterface,
+	auditRepository domain.ProductAuditRepositoryInterface,
+) *GetProductHistoryUseCase {
+	return &GetProductHistoryUseCase{
+		ProductRepository: productRepository,
+		AuditRepository:   auditRepository,
+	}
+}
+
+// Execute returns a page of the changes made to a product, the most recent first. The history of
+// products removed for good is kept. A product without history, created before changes were
+// audited, has an empty one, while an unknown product is not found.
+func (l *GetProductHistoryUseCase) Execute(ctx context.Context, productID string, page, limit int) (_ []ProductAuditEntryOutputDTO, _ int, err error) {
+	ctx, span := tracer.Start(ctx, "GetProductHistoryUseCase.Execute", trace.WithAttributes(
+		attribute.String("product.id", productID), attribute.Int("page", page), attribute.Int("limit", limit)))
+	defer func() { endSpan(span, err) }()
+
+	entries, totalCount, err := l.AuditRepository.ListByProduct(ctx, productID, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	if totalCount == 0 {
+		if _, err = l.ProductRepository.GetByID(ctx, productID); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	output := make([]ProductAuditEntryOutputDTO, 0, len(entries))
+	for _, entry := range entries {
+		output = append(output, newProductAuditEntryOutputDTO(entry))
+	}
+	return output, totalCount, nil
+}
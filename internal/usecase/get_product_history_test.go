@@ -0,0 +1,103 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProductHistoryUseCase(t *testing.T) {
+	repository := database.NewInMemoryProductRepository()
+	auditRepository := database.NewInMemoryProductAuditRepository()
+	outboxRepository := database.NewInMemoryProductOutboxRepository()
+	ctx := domain.WithActor(context.Background(), "alice")
+
+	created, err := usecase.NewCreateProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(ctx, usecase.ProductInputDTO{
+		Name:        "Product 1",
+		Description: "description",
+		Price:       10,
+	})
+	require.NoError(t, err)
+	_, err = usecase.NewUpdateProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(ctx, usecase.ProductUpdateInputDTO{
+		ID:          created.ID,
+		Name:        "Product 1",
+		Description: "description",
+		Price:       12,
+	})
+	require.NoError(t, err)
+	_, err = usecase.NewEnableProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(domain.WithActor(ctx, "bob"), created.ID)
+	require.NoError(t, err)
+	require.NoError(t, usecase.NewDeleteProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(ctx, created.ID, false))
+	_, err = usecase.NewRestoreProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), created.ID)
+	require.NoError(t, err)
+
+	history := usecase.NewGetProductHistoryUseCase(repository, auditRepository)
+	entries, totalCount, err := history.Execute(context.Background(), created.ID, 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, 5, totalCount)
+
+	expected := []struct {
+		operation string
+		actor     string
+		version   int
+		changes   []usecase.ProductFieldChangeDTO
+	}{
+		{entity.AuditRestore, domain.SystemActor, 5, []usecase.ProductFieldChangeDTO{{Field: entity.AuditFieldDeleted, Before: true, After: false}}},
+		{entity.AuditDelete, "alice", 4, []usecase.ProductFieldChangeDTO{{Field: entity.AuditFieldDeleted, Before: false, After: true}}},
+		{entity.AuditEnable, "bob", 3, []usecase.ProductFieldChangeDTO{{Field: entity.AuditFieldStatus, Before: entity.DISABLED, After: entity.ENABLED}}},
+		{entity.AuditUpdate, "alice", 2, []usecase.ProductFieldChangeDTO{{Field: entity.AuditFieldPrice, Before: 10.0, After: 12.0}}},
+		{entity.AuditCreate, "alice", 1, nil},
+	}
+	for i, want := range expected {
+		entry := entries[i]
+		require.Equal(t, created.ID, entry.ProductID)
+		require.Equal(t, want.operation, entry.Operation)
+		require.Equal(t, want.actor, entry.Actor)
+		require.Equal(t, want.version, entry.Version)
+		if want.changes != nil {
+			require.Equal(t, want.changes, entry.Changes)
+		}
+	}
+	require.Len(t, entries[4].Changes, 5)
+
+	t.Run("Pagination", func(t *testing.T) {
+		entries, totalCount, err := history.Execute(context.Background(), created.ID, 2, 2)
+		require.NoError(t, err)
+		require.Equal(t, 5, totalCount)
+		require.Len(t, entries, 2)
+		require.Equal(t, entity.AuditEnable, entries[0].Operation)
+	})
+
+	t.Run("History outlives the product", func(t *testing.T) {
+		require.NoError(t, usecase.NewDeleteProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(ctx, created.ID, true))
+		entries, totalCount, err := history.Execute(context.Background(), created.ID, 1, 1)
+		require.NoError(t, err)
+		require.Equal(t, 6, totalCount)
+		require.Equal(t, entity.AuditDeletePermanently, entries[0].Operation)
+		require.Nil(t, entries[0].Changes[0].After)
+	})
+
+	t.Run("Purged products", func(t *testing.T) {
+		product, err := usecase.NewCreateProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(ctx, usecase.ProductInputDTO{Name: "Product 2", Price: 1})
+		require.NoError(t, err)
+		require.NoError(t, usecase.NewDeleteProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(ctx, product.ID, false))
+		purged, err := usecase.NewPurgeDeletedProductsUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), 0)
+		require.NoError(t, err)
+		require.Equal(t, 1, purged)
+
+		entries, _, err := history.Execute(context.Background(), product.ID, 1, 1)
+		require.NoError(t, err)
+		require.Equal(t, entity.AuditPurge, entries[0].Operation)
+		require.Equal(t, domain.SystemActor, entries[0].Actor)
+	})
+
+	t.Run("Unknown product", func(t *testing.T) {
+		_, _, err := history.Execute(context.Background(), "non-existent-id", 1, 10)
+		require.ErrorIs(t, err, entity.ErrNotFound)
+	})
+}
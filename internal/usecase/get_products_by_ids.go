@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MaxBatchGetIDs is the largest number of ids GetProductsByIDsUseCase looks up at once.
+const MaxBatchGetIDs = 100
+
+type GetProductsByIDsUseCase struct {
+	ProductRepository domain.ProductRepositoryInterface
+}
+
+func NewGetProductsByIDsUseCase(productRepository domain.ProductRepositoryInterface) *GetProductsByIDsUseCase {
+	return &GetProductsByIDsUseCase{
+		ProductRepository: productRepository,
+	}
+}
+
+// Execute looks up the products of input.IDs with a single repository call. Products come back
+// in request order and the ids without a product are listed in MissingIDs, as requested. Ids are
+// compared in their canonical UUID form, so that the same id written in another case or format is
+// looked up and returned once. Ids that are not UUIDs cannot name a product and are reported
+// missing without being looked up.
+func (u *GetProductsByIDsUseCase) Execute(ctx context.Context, input ProductBatchGetInputDTO) (_ ProductBatchGetOutputDTO, err error) {
+	ctx, span := tracer.Start(ctx, "GetProductsByIDsUseCase.Execute", trace.WithAttributes(attribute.Int("ids", len(input.IDs))))
+	defer func() { endSpan(span, err) }()
+
+	if len(input.IDs) == 0 {
+		return ProductBatchGetOutputDTO{}, fmt.Errorf("%w: ids cannot be empty", entity.ErrInvalidInput)
+	}
+	if len(input.IDs) > MaxBatchGetIDs {
+		return ProductBatchGetOutputDTO{}, fmt.Errorf("%w: at most %d ids can be requested at once", entity.ErrInvalidInput, MaxBatchGetIDs)
+	}
+
+	// requested pairs every distinct id with its canonical form, empty when it is not a UUID.
+	type requestedID struct {
+		id, canonical string
+	}
+	var requested []requestedID
+	var ids []string
+	seen := make(map[string]bool, len(input.IDs))
+	for _, id := range input.IDs {
+		if id == "" {
+			return ProductBatchGetOutputDTO{}, fmt.Errorf("%w: ids cannot contain empty values", entity.ErrInvalidInput)
+		}
+		var canonical string
+		if parsed, err := uuid.Parse(id); err == nil {
+			canonical = parsed.String()
+		}
+		key := canonical
+		if key == "" {
+			key = id
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		requested = append(requested, requestedID{id: id, canonical: canonical})
+		if canonical != "" {
+			ids = append(ids, canonical)
+		}
+	}
+
+	var products []*entity.Product
+	if len(ids) > 0 {
+		products, err = u.ProductRepository.GetByIDs(ctx, ids)
+		if err != nil {
+			return ProductBatchGetOutputDTO{}, err
+		}
+	}
+	found := make(map[string]*entity.Product, len(products))
+	for _, product := range products {
+		found[product.GetID()] = product
+	}
+
+	output := ProductBatchGetOutputDTO{
+		Products:   make([]ProductOutputDTO, 0, len(products)),
+		MissingIDs: []string{},
+	}
+	for _, r := range requested {
+		if product, ok := found[r.canonical]; ok {
+			output.Products = append(output.Products, newProductOutputDTO(product))
+		} else {
+			output.MissingIDs = append(output.MissingIDs, r.id)
+		}
+	}
+	return output, nil
+}
@@ -0,0 +1,88 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+	"github.com/stretchr/testify/require"
+)
+
+// unreachableRepository fails the lookups of several products.
+type unreachableRepository struct {
+	domain.ProductRepositoryInterface
+}
+
+func (r unreachableRepository) GetByIDs(ctx context.Context, ids []string) ([]*entity.Product, error) {
+	return nil, errors.New("database unavailable")
+}
+
+func TestGetProductsByIDsUseCase(t *testing.T) {
+	repository := database.NewInMemoryProductRepository()
+	var ids []string
+	for _, name := range []string{"Product A", "Product B", "Product C"} {
+		product, err := entity.NewProduct(name, "description", 10)
+		require.NoError(t, err)
+		require.NoError(t, repository.Create(context.Background(), product))
+		ids = append(ids, product.GetID())
+	}
+	_, err := repository.Delete(context.Background(), ids[2])
+	require.NoError(t, err)
+	getProductsByIDs := usecase.NewGetProductsByIDsUseCase(repository)
+
+	t.Run("Request order and missing ids", func(t *testing.T) {
+		output, err := getProductsByIDs.Execute(context.Background(), usecase.ProductBatchGetInputDTO{
+			IDs: []string{ids[1], "missing", ids[0], ids[2], ids[1]},
+		})
+		require.NoError(t, err)
+		require.Len(t, output.Products, 2)
+		require.Equal(t, ids[1], output.Products[0].ID)
+		require.Equal(t, "Product B", output.Products[0].Name)
+		require.Equal(t, ids[0], output.Products[1].ID)
+		require.Equal(t, []string{"missing", ids[2]}, output.MissingIDs)
+	})
+
+	t.Run("Canonical ids", func(t *testing.T) {
+		upper := strings.ToUpper(ids[0])
+		output, err := getProductsByIDs.Execute(context.Background(), usecase.ProductBatchGetInputDTO{
+			IDs: []string{upper, ids[0], "{" + ids[1] + "}"},
+		})
+		require.NoError(t, err)
+		require.Len(t, output.Products, 2, "the same id in another form is returned once")
+		require.Equal(t, ids[0], output.Products[0].ID)
+		require.Equal(t, ids[1], output.Products[1].ID)
+		require.Empty(t, output.MissingIDs)
+	})
+
+	t.Run("Malformed ids are not looked up", func(t *testing.T) {
+		getProductsByIDs := usecase.NewGetProductsByIDsUseCase(unreachableRepository{repository})
+		output, err := getProductsByIDs.Execute(context.Background(), usecase.ProductBatchGetInputDTO{IDs: []string{"missing", "missing"}})
+		require.NoError(t, err)
+		require.Empty(t, output.Products)
+		require.Equal(t, []string{"missing"}, output.MissingIDs)
+	})
+
+	t.Run("Nothing found", func(t *testing.T) {
+		output, err := getProductsByIDs.Execute(context.Background(), usecase.ProductBatchGetInputDTO{IDs: []string{"missing"}})
+		require.NoError(t, err)
+		require.Empty(t, output.Products)
+		require.NotNil(t, output.Products)
+		require.Equal(t, []string{"missing"}, output.MissingIDs)
+	})
+
+	t.Run("Invalid input", func(t *testing.T) {
+		tooMany := make([]string, usecase.MaxBatchGetIDs+1)
+		for i := range tooMany {
+			tooMany[i] = ids[0]
+		}
+		for _, input := range [][]string{nil, {ids[0], ""}, tooMany} {
+			_, err := getProductsByIDs.Execute(context.Background(), usecase.ProductBatchGetInputDTO{IDs: input})
+			require.ErrorIs(t, err, entity.ErrInvalidInput)
+		}
+	})
+}
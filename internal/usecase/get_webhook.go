@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type GetWebhookUseCase struct {
+	WebhookRepository domain.WebhookRepositoryInterface
+}
+
+func NewGetWebhookUseCase(webhookRepository domain.WebhookRepositoryInterface) *GetWebhookUseCase {
+	return &GetWebhookUseCase{
+		WebhookRepository: webhookRepository,
+	}
+}
+
+// Execute returns the webhook without its secret.
+func (g *GetWebhookUseCase) Execute(ctx context.Context, id string) (_ WebhookOutputDTO, err error) {
+	ctx, span := tracer.Start(ctx, "GetWebhookUseCase.Execute", trace.WithAttributes(attribute.String("webhook.id", id)))
+	defer func() { endSpan(span, err) }()
+
+	webhook, err := g.WebhookRepository.GetByID(ctx, id)
+	if err != nil {
+		return WebhookOutputDTO{}, err
+	}
+	return newWebhookOutputDTO(webhook), nil
+}
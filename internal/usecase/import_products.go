@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Formats accepted by ImportProductsUseCase.
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+)
+
+// Statuses reported for each imported row.
+const (
+	ImportRowCreated = "created"
+	ImportRowValid   = "valid"
+	ImportRowInvalid = "invalid"
+)
+
+// maxNDJSONLineSize bounds the size of a single NDJSON record.
+const maxNDJSONLineSize = 1024 * 1024
+
+type ImportProductsUseCase struct {
+	ProductRepository  domain.ProductRepositoryInterface
+	AuditRepository    domain.ProductAuditRepositoryInterface
+	OutboxRepository   domain.OutboxRepositoryInterface
+	TransactionManager domain.TransactionManager
+	Logger             *slog.Logger
+}
+
+func NewImportProductsUseCase(
+	productRepository domain.ProductRepositoryInterface,
+	auditRepository domain.ProductAuditRepositoryInterface,
+	outboxRepository domain.OutboxRepositoryInterface,
+	transactionManager domain.TransactionManager,
+	logger *slog.Logger,
+) *ImportProductsUseCase {
+	return &ImportProductsUseCase{
+		ProductRepository:  productRepository,
+		AuditRepository:    auditRepository,
+		OutboxRepository:   outboxRepository,
+		TransactionManager: transactionManager,
+		Logger:             logger,
+	}
+}
+
+// Execute validates every row of the input through entity.NewProduct and, unless DryRun is set,
+// stores the valid ones in a single batch. Invalid rows never prevent valid ones from being imported.
+func (u *ImportProductsUseCase) Execute(ctx context.Context, input ProductImportInputDTO) (_ ProductImportOutputDTO, err error) {
+	ctx, span := tracer.Start(ctx, "ImportProductsUseCase.Execute", trace.WithAttributes(
+		attribute.String("import.format", input.Format), attribute.Bool("import.dry_run", input.DryRun)))
+	defer func() { endSpan(span, err) }()
+
+	rows, err := readImportRows(input.Format, input.Reader)
+	if err != nil {
+		return ProductImportOutputDTO{}, err
+	}
+
+	output := ProductImportOutputDTO{
+		DryRun: input.DryRun,
+		Total:  len(rows),
+		Rows:   make([]ProductImportRowOutputDTO, 0, len(rows)),
+	}
+
+	var products []*entity.Product
+	for _, row := range rows {
+		result := ProductImportRowOutputDTO{Line: row.line}
+		if row.err != nil {
+			result.Status = ImportRowInvalid
+			result.Errors = []ProductImportErrorDTO{importError(row.err)}
+			output.Rows = append(output.Rows, result)
+			output.Invalid++
+			continue
+		}
+
+		product, err := entity.NewProduct(row.input.Name, row.input.Description, row.input.Price)
+		if err != nil {
+			result.Status = ImportRowInvalid
+			result.Errors = []ProductImportErrorDTO{importError(err)}
+			output.Rows = append(output.Rows, result)
+			output.Invalid++
+			continue
+		}
+
+		result.Status = ImportRowValid
+		if !input.DryRun {
+			result.Status = ImportRowCreated
+			result.ID = product.GetID()
+			products = append(products, product)
+		}
+		output.Rows = append(output.Rows, result)
+		output.Valid++
+	}
+
+	if len(products) > 0 {
+		err = u.TransactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := u.ProductRepository.CreateBatch(ctx, products); err != nil {
+				return err
+			}
+			entries := make([]*entity.ProductAuditEntry, len(products))
+			for i, product := range products {
+				entries[i] = newAuditEntry(ctx, entity.AuditCreate, product, entity.DiffProducts(nil, product))
+			}
+			if err := u.AuditRepository.Append(ctx, entries...); err != nil {
+				return err
+			}
+			return u.OutboxRepository.Append(ctx, pullEvents(products...)...)
+		})
+		if err != nil {
+			return ProductImportOutputDTO{}, err
+		}
+		output.Created = len(products)
+	}
+	u.Logger.InfoContext(ctx, "products imported",
+		"format", input.Format, "dry_run", input.DryRun, "total", output.Total,
+		"valid", output.Valid, "invalid", output.Invalid, "created", output.Created)
+
+	return output, nil
+}
+
+// importRow is a single record read from the import payload, along with the
+// line it came from and any error found while parsing it.
+type importRow struct {
+	line  int
+	input ProductInputDTO
+	err   error
+}
+
+func readImportRows(format string, r io.Reader) ([]importRow, error) {
+	switch format {
+	case ImportFormatCSV:
+		return readCSVRows(r)
+	case ImportFormatNDJSON:
+		return readNDJSONRows(r)
+	default:
+		return nil, fmt.Errorf("%w: unsupported import format %q", entity.ErrInvalidInput, format)
+	}
+}
+
+// readCSVRows reads a CSV payload whose first line is a header naming the
+// name, description and price columns, in any order. Description is optional.
+func readCSVRows(r io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: empty CSV payload", entity.ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid CSV header: %w", entity.ErrInvalidInput, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, required := range []string{"name", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: CSV header must contain a %q column", entity.ErrInvalidInput, required)
+		}
+	}
+
+	column := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			rows = append(rows, importRow{line: parseErr.StartLine, err: err})
+			continue
+		}
+		// FieldPos panics unless the last call to Read succeeded
+		line, _ := reader.FieldPos(0)
+
+		row := importRow{
+			line: line,
+			input: ProductInputDTO{
+				Name:        column(record, "name"),
+				Description: column(record, "description"),
+			},
+		}
+		price, err := strconv.ParseFloat(column(record, "price"), 64)
+		if err != nil {
+			row.err = entity.NewValidationError("price", "price must be a number")
+		}
+		row.input.Price = price
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// readNDJSONRows reads one JSON encoded ProductInputDTO per line. Blank lines are ignored.
+func readNDJSONRows(r io.Reader) ([]importRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+
+	var rows []importRow
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		row := importRow{line: line}
+		err := json.Unmarshal(data, &row.input)
+		if err != nil {
+			row.err = fmt.Errorf("invalid JSON: %s", err)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", entity.ErrInvalidInput, err)
+	}
+	return rows, nil
+}
+
+func importError(err error) ProductImportErrorDTO {
+	var validationErr *entity.ValidationError
+	if errors.As(err, &validationErr) {
+		return ProductImportErrorDTO{Field: validationErr.Field, Message: validationErr.Message}
+	}
+	return ProductImportErrorDTO{Message: err.Error()}
+}
@@ -0,0 +1,136 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportProductsUseCase(t *testing.T) {
+	t.Run("Import CSV", func(t *testing.T) {
+		repository := database.NewInMemoryProductRepository()
+		auditRepository := database.NewInMemoryProductAuditRepository()
+		outboxRepository := database.NewInMemoryProductOutboxRepository()
+		payload := "name,description,price\n" +
+			"Product A,Description A,10.5\n" +
+			",Missing name,20\n" +
+			"Product C,Description C,abc\n" +
+			"Product D,,0\n"
+
+		output, err := usecase.NewImportProductsUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), usecase.ProductImportInputDTO{
+			Format: usecase.ImportFormatCSV,
+			Reader: strings.NewReader(payload),
+		})
+		require.Nil(t, err)
+		require.Equal(t, 4, output.Total)
+		require.Equal(t, 2, output.Valid)
+		require.Equal(t, 2, output.Invalid)
+		require.Equal(t, 2, output.Created)
+
+		require.Equal(t, 2, output.Rows[0].Line)
+		require.Equal(t, usecase.ImportRowCreated, output.Rows[0].Status)
+		require.NotEmpty(t, output.Rows[0].ID)
+
+		require.Equal(t, usecase.ImportRowInvalid, output.Rows[1].Status)
+		require.Equal(t, []usecase.ProductImportErrorDTO{{Field: "name", Message: "name cannot be empty"}}, output.Rows[1].Errors)
+		require.Equal(t, "price", output.Rows[2].Errors[0].Field)
+
+		product, err := repository.GetByID(context.Background(), output.Rows[0].ID)
+		require.Nil(t, err)
+		require.Equal(t, "Product A", product.GetName())
+		require.Equal(t, 10.5, product.GetPrice())
+
+		_, totalCount, err := repository.List(context.Background(), domain.ProductFilter{}, 1, 10, domain.ProductSort{{Field: domain.SortByID}})
+		require.Nil(t, err)
+		require.Equal(t, 2, totalCount)
+	})
+
+	t.Run("Import CSV rejects NaN and Inf prices", func(t *testing.T) {
+		repository := database.NewInMemoryProductRepository()
+		payload := "name,price\nA,NaN\nB,Inf\nC,-Inf\n"
+
+		output, err := usecase.NewImportProductsUseCase(repository, database.NewInMemoryProductAuditRepository(), database.NewInMemoryProductOutboxRepository(), transactionManager, discardLogger).Execute(context.Background(), usecase.ProductImportInputDTO{
+			Format: usecase.ImportFormatCSV,
+			Reader: strings.NewReader(payload),
+		})
+		require.Nil(t, err)
+		require.Equal(t, 3, output.Invalid)
+		require.Equal(t, 0, output.Created)
+		for _, row := range output.Rows {
+			require.Equal(t, usecase.ImportRowInvalid, row.Status)
+			require.Equal(t, []usecase.ProductImportErrorDTO{{Field: "price", Message: "price must be a finite number"}}, row.Errors)
+		}
+	})
+
+	t.Run("Import CSV with a malformed row", func(t *testing.T) {
+		repository := database.NewInMemoryProductRepository()
+		payload := "name,price\n\"A\"x,1\nB,2\n"
+
+		output, err := usecase.NewImportProductsUseCase(repository, database.NewInMemoryProductAuditRepository(), database.NewInMemoryProductOutboxRepository(), transactionManager, discardLogger).Execute(context.Background(), usecase.ProductImportInputDTO{
+			Format: usecase.ImportFormatCSV,
+			Reader: strings.NewReader(payload),
+		})
+		require.Nil(t, err)
+		require.Equal(t, 2, output.Total)
+		require.Equal(t, 1, output.Invalid)
+		require.Equal(t, 1, output.Created)
+
+		require.Equal(t, 2, output.Rows[0].Line)
+		require.Equal(t, usecase.ImportRowInvalid, output.Rows[0].Status)
+		require.Len(t, output.Rows[0].Errors, 1)
+		require.Contains(t, output.Rows[0].Errors[0].Message, "parse error on line 2")
+
+		require.Equal(t, 3, output.Rows[1].Line)
+		require.Equal(t, usecase.ImportRowCreated, output.Rows[1].Status)
+		product, err := repository.GetByID(context.Background(), output.Rows[1].ID)
+		require.Nil(t, err)
+		require.Equal(t, "B", product.GetName())
+	})
+
+	t.Run("Import NDJSON dry run", func(t *testing.T) {
+		repository := database.NewInMemoryProductRepository()
+		auditRepository := database.NewInMemoryProductAuditRepository()
+		outboxRepository := database.NewInMemoryProductOutboxRepository()
+		payload := `{"name":"Product A","description":"Description A","price":10}` + "\n" +
+			"\n" +
+			`{"name":"Product B","price":-1}` + "\n" +
+			`not json` + "\n"
+
+		output, err := usecase.NewImportProductsUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), usecase.ProductImportInputDTO{
+			Format: usecase.ImportFormatNDJSON,
+			Reader: strings.NewReader(payload),
+			DryRun: true,
+		})
+		require.Nil(t, err)
+		require.True(t, output.DryRun)
+		require.Equal(t, 3, output.Total)
+		require.Equal(t, 1, output.Valid)
+		require.Equal(t, 0, output.Created)
+		require.Equal(t, usecase.ImportRowValid, output.Rows[0].Status)
+		require.Empty(t, output.Rows[0].ID)
+		require.Equal(t, 3, output.Rows[1].Line)
+		require.Equal(t, "price", output.Rows[1].Errors[0].Field)
+		require.Equal(t, 4, output.Rows[2].Line)
+
+		_, totalCount, err := repository.List(context.Background(), domain.ProductFilter{}, 1, 10, domain.ProductSort{{Field: domain.SortByID}})
+		require.Nil(t, err)
+		require.Equal(t, 0, totalCount)
+	})
+
+	t.Run("CSV without required columns", func(t *testing.T) {
+		repository := database.NewInMemoryProductRepository()
+		auditRepository := database.NewInMemoryProductAuditRepository()
+		outboxRepository := database.NewInMemoryProductOutboxRepository()
+		_, err := usecase.NewImportProductsUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), usecase.ProductImportInputDTO{
+			Format: usecase.ImportFormatCSV,
+			Reader: strings.NewReader("title,cost\nProduct A,10\n"),
+		})
+		require.ErrorIs(t, err, entity.ErrInvalidInput)
+	})
+}
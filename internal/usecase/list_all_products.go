@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type ListAllProductsUseCase struct {
+	ProductRepository domain.ProductRepositoryInterface
+}
+
+func NewListAllProductsUseCase(productRepository domain.ProductRepositoryInterface) *ListAllProductsUseCase {
+	return &ListAllProductsUseCase{
+		ProductRepository: productRepository,
+	}
+}
+
+// Execute calls fn for every product matching filter, ordered by id, one product at a time so
+// that the whole catalog is never loaded into memory. It stops at the first error returned by fn.
+func (u *ListAllProductsUseCase) Execute(ctx context.Context, filter domain.ProductFilter, fn func(ProductOutputDTO) error) (err error) {
+	ctx, span := tracer.Start(ctx, "ListAllProductsUseCase.Execute")
+	defer func() { endSpan(span, err) }()
+
+	err = filter.Validate()
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	defer func() { span.SetAttributes(attribute.Int("products", count)) }()
+	return u.ProductRepository.ForEach(ctx, filter, func(product *entity.Product) error {
+		count++
+		return fn(newProductOutputDTO(product))
+	})
+}
@@ -0,0 +1,52 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAllProductsUseCase(t *testing.T) {
+	repository := database.NewInMemoryProductRepository()
+	for _, name := range []string{"Product A", "Product B", "Product C"} {
+		product, err := entity.NewProduct(name, "description", 10)
+		require.NoError(t, err)
+		require.NoError(t, repository.Create(context.Background(), product))
+	}
+	listAllProducts := usecase.NewListAllProductsUseCase(repository)
+
+	t.Run("Every product, ordered by id", func(t *testing.T) {
+		var products []usecase.ProductOutputDTO
+		err := listAllProducts.Execute(context.Background(), domain.ProductFilter{}, func(product usecase.ProductOutputDTO) error {
+			products = append(products, product)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, products, 3)
+		require.Less(t, products[0].ID, products[1].ID)
+		require.Less(t, products[1].ID, products[2].ID)
+	})
+
+	t.Run("Stops at the first error", func(t *testing.T) {
+		calls := 0
+		err := listAllProducts.Execute(context.Background(), domain.ProductFilter{}, func(usecase.ProductOutputDTO) error {
+			calls++
+			return errors.New("client gone")
+		})
+		require.EqualError(t, err, "client gone")
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("Invalid filter", func(t *testing.T) {
+		err := listAllProducts.Execute(context.Background(), domain.ProductFilter{Status: "archived"}, func(usecase.ProductOutputDTO) error {
+			return nil
+		})
+		require.ErrorIs(t, err, entity.ErrInvalidInput)
+	})
+}
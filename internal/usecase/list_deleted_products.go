@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ListDeletedProductsUseCase struct {
+	ProductRepository domain.ProductRepositoryInterface
+}
+
+func NewListDeletedProductsUseCase(productRepository domain.ProductRepositoryInterface) *ListDeletedProductsUseCase {
+	return &ListDeletedProductsUseCase{
+		ProductRepository: productRepository,
+	}
+}
+
+// Execute returns a page of the products in the trash, most recently deleted first.
+func (l *ListDeletedProductsUseCase) Execute(ctx context.Context, page, limit int) (_ []ProductOutputDTO, _ int, err error) {
+	ctx, span := tracer.Start(ctx, "ListDeletedProductsUseCase.Execute", trace.WithAttributes(
+		attribute.Int("page", page), attribute.Int("limit", limit)))
+	defer func() { endSpan(span, err) }()
+
+	products, totalCount, err := l.ProductRepository.ListDeleted(ctx, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var outputProducts []ProductOutputDTO
+	for _, product := range products {
+		outputProducts = append(outputProducts, newProductOutputDTO(product))
+	}
+	return outputProducts, totalCount, nil
+}
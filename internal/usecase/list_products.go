@@ -1,7 +1,11 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/HaroldoFV/product-service/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type ListProductsUseCase struct {
@@ -14,21 +18,31 @@ func NewListProductsUseCase(productRepository domain.ProductRepositoryInterface)
 	}
 }
 
-func (l *ListProductsUseCase) Execute(page, limit int, sort string) ([]ProductOutputDTO, int, error) {
-	products, totalCount, err := l.ProductRepository.List(page, limit, sort)
+// Execute returns a page of the products matching filter. sort is a comma separated list of
+// fields, each optionally prefixed with "-" for descending order.
+func (l *ListProductsUseCase) Execute(ctx context.Context, filter domain.ProductFilter, page, limit int, sort string) (_ []ProductOutputDTO, _ int, err error) {
+	ctx, span := tracer.Start(ctx, "ListProductsUseCase.Execute", trace.WithAttributes(
+		attribute.Int("page", page), attribute.Int("limit", limit), attribute.String("sort", sort)))
+	defer func() { endSpan(span, err) }()
+
+	err = filter.Validate()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	productSort, err := domain.ParseProductSort(sort)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	products, totalCount, err := l.ProductRepository.List(ctx, filter, page, limit, productSort)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	var outputProducts []ProductOutputDTO
 	for _, product := range products {
-		outputProducts = append(outputProducts, ProductOutputDTO{
-			ID:          product.GetID(),
-			Name:        product.GetName(),
-			Description: product.GetDescription(),
-			Price:       product.GetPrice(),
-			Status:      product.GetStatus(),
-		})
+		outputProducts = append(outputProducts, newProductOutputDTO(product))
 	}
 	return outputProducts, totalCount, nil
 }
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ListProductsByCursorUseCase struct {
+	ProductRepository domain.ProductRepositoryInterface
+	CursorCodec       *CursorCodec
+}
+
+func NewListProductsByCursorUseCase(
+	productRepository domain.ProductRepositoryInterface,
+	cursorCodec *CursorCodec,
+) *ListProductsByCursorUseCase {
+	return &ListProductsByCursorUseCase{
+		ProductRepository: productRepository,
+		CursorCodec:       cursorCodec,
+	}
+}
+
+// Execute returns the page of products following (or preceding) input.Cursor. An empty cursor
+// starts at the first page using input.Sort; otherwise the sort stored in the cursor is used.
+func (l *ListProductsByCursorUseCase) Execute(ctx context.Context, input ProductCursorListInputDTO) (_ ProductCursorListOutputDTO, err error) {
+	ctx, span := tracer.Start(ctx, "ListProductsByCursorUseCase.Execute", trace.WithAttributes(
+		attribute.Int("limit", input.Limit), attribute.Bool("cursor", input.Cursor != "")))
+	defer func() { endSpan(span, err) }()
+
+	err = input.Filter.Validate()
+	if err != nil {
+		return ProductCursorListOutputDTO{}, err
+	}
+
+	var sort domain.ProductSort
+	var cursor *domain.ProductCursor
+	if input.Cursor != "" {
+		decoded, err := l.CursorCodec.Decode(input.Cursor)
+		if err != nil {
+			return ProductCursorListOutputDTO{}, err
+		}
+		cursor = &decoded
+		sort = decoded.Sort
+	} else {
+		sort, err = domain.ParseProductSort(input.Sort)
+		if err != nil {
+			return ProductCursorListOutputDTO{}, err
+		}
+	}
+
+	// Fetching one extra product tells whether there is another page in the same direction.
+	products, err := l.ProductRepository.ListByCursor(ctx, input.Filter, sort, cursor, input.Limit+1)
+	if err != nil {
+		return ProductCursorListOutputDTO{}, err
+	}
+	hasMore := len(products) > input.Limit
+	backward := cursor != nil && cursor.Backward
+	if hasMore {
+		if backward {
+			products = products[1:]
+		} else {
+			products = products[:input.Limit]
+		}
+	}
+
+	output := ProductCursorListOutputDTO{Products: make([]ProductOutputDTO, 0, len(products))}
+	for _, product := range products {
+		output.Products = append(output.Products, newProductOutputDTO(product))
+	}
+	if len(products) == 0 {
+		return output, nil
+	}
+
+	first, last := products[0], products[len(products)-1]
+	if hasMore || backward {
+		output.NextCursor = l.CursorCodec.Encode(domain.NewProductCursor(last, sort, false))
+	}
+	if (hasMore && backward) || (cursor != nil && !backward) {
+		output.PrevCursor = l.CursorCodec.Encode(domain.NewProductCursor(first, sort, true))
+	}
+	return output, nil
+}
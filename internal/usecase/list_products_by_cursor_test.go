@@ -0,0 +1,124 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorCodec(t *testing.T) {
+	codec := usecase.NewCursorCodec([]byte("secret"))
+	sort, err := domain.ParseProductSort("-price,name")
+	require.Nil(t, err)
+	cursor := domain.ProductCursor{Sort: sort, Values: []string{"10.5", "Chair", "123"}, Backward: true}
+
+	t.Run("Round trip", func(t *testing.T) {
+		decoded, err := codec.Decode(codec.Encode(cursor))
+		require.Nil(t, err)
+		require.Equal(t, cursor, decoded)
+	})
+
+	t.Run("Tampered cursor", func(t *testing.T) {
+		token := codec.Encode(cursor)
+		_, err := codec.Decode("x" + token)
+		require.ErrorIs(t, err, entity.ErrInvalidInput)
+	})
+
+	t.Run("Cursor signed with another secret", func(t *testing.T) {
+		token := usecase.NewCursorCodec([]byte("other secret")).Encode(cursor)
+		_, err := codec.Decode(token)
+		require.ErrorIs(t, err, entity.ErrInvalidInput)
+	})
+
+	t.Run("Cursor values not matching its sort", func(t *testing.T) {
+		token := codec.Encode(domain.ProductCursor{Sort: sort, Values: []string{"10.5"}})
+		_, err := codec.Decode(token)
+		require.ErrorIs(t, err, entity.ErrInvalidInput)
+	})
+
+	t.Run("Malformed cursor", func(t *testing.T) {
+		_, err := codec.Decode("not-a-cursor")
+		require.ErrorIs(t, err, entity.ErrInvalidInput)
+	})
+}
+
+func TestListProductsByCursorUseCase(t *testing.T) {
+	repository := database.NewInMemoryProductRepository()
+	for i := 1; i <= 7; i++ {
+		product, err := entity.NewProduct(fmt.Sprintf("Product %d", i), "description", float64(i%3))
+		require.Nil(t, err)
+		require.Nil(t, repository.Create(context.Background(), product))
+	}
+	listProducts := usecase.NewListProductsByCursorUseCase(repository, usecase.NewCursorCodec([]byte("secret")))
+
+	collect := func(output usecase.ProductCursorListOutputDTO) []string {
+		var ids []string
+		for _, product := range output.Products {
+			ids = append(ids, product.ID)
+		}
+		return ids
+	}
+
+	for _, sort := range []string{"id", "price", "name", "-price,name", "-created_at"} {
+		t.Run("Walk pages sorted by "+sort, func(t *testing.T) {
+			productSort, err := domain.ParseProductSort(sort)
+			require.Nil(t, err)
+			expected, _, err := repository.List(context.Background(), domain.ProductFilter{}, 1, 10, productSort)
+			require.Nil(t, err)
+			var expectedIDs []string
+			for _, product := range expected {
+				expectedIDs = append(expectedIDs, product.GetID())
+			}
+
+			// Forward: 3 + 3 + 1
+			var pages [][]string
+			var prevCursors []string
+			output, err := listProducts.Execute(context.Background(), usecase.ProductCursorListInputDTO{Sort: sort, Limit: 3})
+			require.Nil(t, err)
+			require.Empty(t, output.PrevCursor)
+			pages = append(pages, collect(output))
+			for output.NextCursor != "" {
+				output, err = listProducts.Execute(context.Background(), usecase.ProductCursorListInputDTO{Sort: "ignored", Cursor: output.NextCursor, Limit: 3})
+				require.Nil(t, err)
+				require.NotEmpty(t, output.PrevCursor)
+				prevCursors = append(prevCursors, output.PrevCursor)
+				pages = append(pages, collect(output))
+			}
+			require.Len(t, pages, 3)
+
+			var walked []string
+			for _, page := range pages {
+				walked = append(walked, page...)
+			}
+			require.Equal(t, expectedIDs, walked)
+
+			// Backward from the last page returns the previous pages
+			output, err = listProducts.Execute(context.Background(), usecase.ProductCursorListInputDTO{Cursor: prevCursors[1], Limit: 3})
+			require.Nil(t, err)
+			require.Equal(t, pages[1], collect(output))
+			require.NotEmpty(t, output.NextCursor)
+			require.NotEmpty(t, output.PrevCursor)
+
+			output, err = listProducts.Execute(context.Background(), usecase.ProductCursorListInputDTO{Cursor: output.PrevCursor, Limit: 3})
+			require.Nil(t, err)
+			require.Equal(t, pages[0], collect(output))
+			require.Empty(t, output.PrevCursor)
+		})
+	}
+
+	t.Run("Invalid sort", func(t *testing.T) {
+		_, err := listProducts.Execute(context.Background(), usecase.ProductCursorListInputDTO{Sort: "-weight", Limit: 3})
+		require.ErrorIs(t, err, entity.ErrInvalidInput)
+	})
+
+	t.Run("Invalid cursor", func(t *testing.T) {
+		_, err := listProducts.Execute(context.Background(), usecase.ProductCursorListInputDTO{Cursor: "invalid", Limit: 3})
+		require.ErrorIs(t, err, entity.ErrInvalidInput)
+	})
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// deliveryStatuses are the statuses accepted by ListWebhookDeliveriesUseCase.
+var deliveryStatuses = []string{entity.DeliveryPending, entity.DeliveryDelivered, entity.DeliveryDead}
+
+type ListWebhookDeliveriesUseCase struct {
+	WebhookRepository domain.WebhookRepositoryInterface
+}
+
+func NewListWebhookDeliveriesUseCase(webhookRepository domain.WebhookRepositoryInterface) *ListWebhookDeliveriesUseCase {
+	return &ListWebhookDeliveriesUseCase{
+		WebhookRepository: webhookRepository,
+	}
+}
+
+// Execute returns a page of the deliveries of a webhook, or of every webhook when webhookID is
+// empty, most recent first. An empty status matches every delivery; dead lists the dead letters.
+func (l *ListWebhookDeliveriesUseCase) Execute(ctx context.Context, webhookID, status string, page, limit int) (_ []WebhookDeliveryOutputDTO, _ int, err error) {
+	ctx, span := tracer.Start(ctx, "ListWebhookDeliveriesUseCase.Execute", trace.WithAttributes(
+		attribute.String("webhook.id", webhookID), attribute.String("status", status),
+		attribute.Int("page", page), attribute.Int("limit", limit)))
+	defer func() { endSpan(span, err) }()
+
+	if status != "" && !slices.Contains(deliveryStatuses, status) {
+		return nil, 0, fmt.Errorf("%w: invalid status %q, expected pending, delivered or dead", entity.ErrInvalidInput, status)
+	}
+	if webhookID != "" {
+		if _, err = l.WebhookRepository.GetByID(ctx, webhookID); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	deliveries, totalCount, err := l.WebhookRepository.ListDeliveries(ctx, domain.WebhookDeliveryFilter{WebhookID: webhookID, Status: status}, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	output := make([]WebhookDeliveryOutputDTO, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		output = append(output, newWebhookDeliveryOutputDTO(delivery))
+	}
+	return output, totalCount, nil
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+)
+
+type ListWebhooksUseCase struct {
+	WebhookRepository domain.WebhookRepositoryInterface
+}
+
+func NewListWebhooksUseCase(webhookRepository domain.WebhookRepositoryInterface) *ListWebhooksUseCase {
+	return &ListWebhooksUseCase{
+		WebhookRepository: webhookRepository,
+	}
+}
+
+// Execute returns every webhook, oldest first, without their secrets.
+func (l *ListWebhooksUseCase) Execute(ctx context.Context) (_ []WebhookOutputDTO, err error) {
+	ctx, span := tracer.Start(ctx, "ListWebhooksUseCase.Execute")
+	defer func() { endSpan(span, err) }()
+
+	webhooks, err := l.WebhookRepository.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]WebhookOutputDTO, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		output = append(output, newWebhookOutputDTO(webhook))
+	}
+	return output, nil
+}
@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Patch formats accepted by PatchProductUseCase.
+const (
+	// PatchFormatMerge is a JSON Merge Patch (RFC 7396).
+	PatchFormatMerge = "merge-patch"
+	// PatchFormatJSON is a JSON Patch (RFC 6902).
+	PatchFormatJSON = "json-patch"
+)
+
+type PatchProductUseCase struct {
+	ProductRepository  domain.ProductRepositoryInterface
+	AuditRepository    domain.ProductAuditRepositoryInterface
+	OutboxRepository   domain.OutboxRepositoryInterface
+	TransactionManager domain.TransactionManager
+	Logger             *slog.Logger
+}
+
+func NewPatchProductUseCase(
+	productRepository domain.ProductRepositoryInterface,
+	auditRepository domain.ProductAuditRepositoryInterface,
+	outboxRepository domain.OutboxRepositoryInterface,
+	transactionManager domain.TransactionManager,
+	logger *slog.Logger,
+) *PatchProductUseCase {
+	return &PatchProductUseCase{
+		ProductRepository:  productRepository,
+		AuditRepository:    auditRepository,
+		OutboxRepository:   outboxRepository,
+		TransactionManager: transactionManager,
+		Logger:             logger,
+	}
+}
+
+// productDocument is the JSON representation of a product the patch is applied to, the same
+// returned by the API. Pointers tell removed fields apart from empty ones.
+type productDocument struct {
+	ID          *string    `json:"id"`
+	Name        *string    `json:"name"`
+	Description *string    `json:"description"`
+	Price       *float64   `json:"price"`
+	Status      *string    `json:"status"`
+	CreatedAt   *time.Time `json:"created_at"`
+	Version     *int       `json:"version"`
+}
+
+// Execute applies input.Patch to the product as returned by the API. Only name, description and
+// price can change; the other fields may be read, for instance by a JSON Patch test operation,
+// but not modified. Changes go through Product.Update and Product.ChangePrice, so the patched
+// product is validated like any other. A patch that changes nothing does not write the product.
+func (u *PatchProductUseCase) Execute(ctx context.Context, input ProductPatchInputDTO) (_ ProductOutputDTO, err error) {
+	ctx, span := tracer.Start(ctx, "PatchProductUseCase.Execute", trace.WithAttributes(
+		attribute.String("product.id", input.ID), attribute.String("patch.format", input.Format)))
+	defer func() { endSpan(span, err) }()
+
+	product, err := u.ProductRepository.GetByID(ctx, input.ID)
+	if err != nil {
+		return ProductOutputDTO{}, err
+	}
+	if err = checkIfMatch(product, input.IfMatch); err != nil {
+		return ProductOutputDTO{}, err
+	}
+
+	current := newProductOutputDTO(product)
+	patched, err := applyPatch(current, input.Format, input.Patch)
+	if err != nil {
+		return ProductOutputDTO{}, err
+	}
+
+	name, description, price, err := patchedFields(current, patched)
+	if err != nil {
+		return ProductOutputDTO{}, err
+	}
+	if name == current.Name && description == current.Description && price == current.Price {
+		return current, nil
+	}
+
+	before := *product
+	if name != current.Name || description != current.Description {
+		if err = product.Update(name, description); err != nil {
+			return ProductOutputDTO{}, err
+		}
+	}
+	if price != current.Price {
+		if err = product.ChangePrice(price); err != nil {
+			return ProductOutputDTO{}, err
+		}
+	}
+
+	err = u.TransactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.ProductRepository.Update(ctx, product); err != nil {
+			return versionConflictError(err, input.IfMatch)
+		}
+		if err := u.AuditRepository.Append(ctx, newAuditEntry(ctx, entity.AuditPatch, product, entity.DiffProducts(&before, product))); err != nil {
+			return err
+		}
+		return u.OutboxRepository.Append(ctx, product.PullEvents()...)
+	})
+	if err != nil {
+		return ProductOutputDTO{}, err
+	}
+	u.Logger.InfoContext(ctx, "product patched", "product_id", product.GetID(), "format", input.Format)
+
+	return newProductOutputDTO(product), nil
+}
+
+// applyPatch applies patch to the JSON representation of current. A malformed patch is invalid
+// input, while a well formed one that cannot be applied, such as a failed test operation or a
+// path that does not exist, conflicts with the current state of the product.
+func applyPatch(current ProductOutputDTO, format string, patch []byte) (productDocument, error) {
+	document, err := json.Marshal(current)
+	if err != nil {
+		return productDocument{}, err
+	}
+
+	switch format {
+	case PatchFormatMerge:
+		if !json.Valid(patch) || !bytes.HasPrefix(bytes.TrimSpace(patch), []byte("{")) {
+			return productDocument{}, fmt.Errorf("%w: merge patch must be a JSON object", entity.ErrInvalidInput)
+		}
+		document, err = jsonpatch.MergePatch(document, patch)
+		if err != nil {
+			return productDocument{}, fmt.Errorf("%w: %s", entity.ErrInvalidInput, err)
+		}
+	case PatchFormatJSON:
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return productDocument{}, fmt.Errorf("%w: invalid JSON patch: %s", entity.ErrInvalidInput, err)
+		}
+		document, err = operations.Apply(document)
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return productDocument{}, fmt.Errorf("%w: %s", entity.ErrConflict, err)
+		}
+		if err != nil {
+			return productDocument{}, fmt.Errorf("%w: JSON patch cannot be applied: %s", entity.ErrConflict, err)
+		}
+	default:
+		return productDocument{}, fmt.Errorf("%w: unsupported patch format %q", entity.ErrInvalidInput, format)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.DisallowUnknownFields()
+	var patched productDocument
+	if err = decoder.Decode(&patched); err != nil {
+		return productDocument{}, fmt.Errorf("%w: %s", entity.ErrInvalidInput, err)
+	}
+	return patched, nil
+}
+
+// patchedFields returns the name, description and price of the patched document, after making
+// sure no read-only field was changed. A removed name or description becomes empty, which is
+// then validated by the entity, while price cannot be removed.
+func patchedFields(current ProductOutputDTO, patched productDocument) (string, string, float64, error) {
+	readOnly := []struct {
+		field     string
+		unchanged bool
+	}{
+		{"id", patched.ID != nil && *patched.ID == current.ID},
+		{"status", patched.Status != nil && *patched.Status == current.Status},
+		{"created_at", patched.CreatedAt != nil && patched.CreatedAt.Equal(current.CreatedAt)},
+		{"version", patched.Version != nil && *patched.Version == current.Version},
+	}
+	for _, field := range readOnly {
+		if !field.unchanged {
+			return "", "", 0, entity.NewValidationError(field.field, field.field+" cannot be changed")
+		}
+	}
+
+	if patched.Price == nil {
+		return "", "", 0, entity.NewValidationError("price", "price cannot be removed")
+	}
+	var name, description string
+	if patched.Name != nil {
+		name = *patched.Name
+	}
+	if patched.Description != nil {
+		description = *patched.Description
+	}
+	return name, description, *patched.Price, nil
+}
@@ -0,0 +1,137 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchProductUseCase(t *testing.T) {
+	repository := database.NewInMemoryProductRepository()
+	auditRepository := database.NewInMemoryProductAuditRepository()
+	outboxRepository := database.NewInMemoryProductOutboxRepository()
+	created, err := usecase.NewCreateProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), usecase.ProductInputDTO{
+		Name:        "Product 1",
+		Description: "description",
+		Price:       99.99,
+	})
+	require.Nil(t, err)
+
+	patch := func(format, body string, ifMatch ...int) (usecase.ProductOutputDTO, error) {
+		return usecase.NewPatchProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), usecase.ProductPatchInputDTO{
+			ID:      created.ID,
+			Format:  format,
+			Patch:   []byte(body),
+			IfMatch: ifMatch,
+		})
+	}
+
+	t.Run("Merge patch changes only the supplied fields", func(t *testing.T) {
+		output, err := patch(usecase.PatchFormatMerge, `{"price": 120}`)
+		require.Nil(t, err)
+		require.Equal(t, "Product 1", output.Name)
+		require.Equal(t, "description", output.Description)
+		require.Equal(t, 120.0, output.Price)
+		require.Equal(t, created.Version+1, output.Version)
+	})
+
+	t.Run("Merge patch removing the description", func(t *testing.T) {
+		output, err := patch(usecase.PatchFormatMerge, `{"description": null}`)
+		require.Nil(t, err)
+		require.Equal(t, "", output.Description)
+		require.Equal(t, 120.0, output.Price)
+	})
+
+	t.Run("Patch that changes nothing", func(t *testing.T) {
+		before, err := usecase.NewGetProductUseCase(repository).Execute(context.Background(), created.ID)
+		require.Nil(t, err)
+
+		output, err := patch(usecase.PatchFormatMerge, `{"name": "Product 1"}`)
+		require.Nil(t, err)
+		require.Equal(t, before, output)
+	})
+
+	t.Run("Invalid merge patches", func(t *testing.T) {
+		testCases := []struct {
+			name  string
+			patch string
+			err   error
+			field string
+		}{
+			{"Removed name", `{"name": null}`, entity.ErrValidation, "name"},
+			{"Removed price", `{"price": null}`, entity.ErrValidation, "price"},
+			{"Negative price", `{"price": -1}`, entity.ErrValidation, "price"},
+			{"Read-only status", `{"status": "enabled"}`, entity.ErrValidation, "status"},
+			{"Read-only version", `{"version": 10}`, entity.ErrValidation, "version"},
+			{"Unknown field", `{"color": "red"}`, entity.ErrInvalidInput, ""},
+			{"Wrong type", `{"price": "cheap"}`, entity.ErrInvalidInput, ""},
+			{"Not an object", `["price"]`, entity.ErrInvalidInput, ""},
+			{"Malformed", `{"price":`, entity.ErrInvalidInput, ""},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := patch(usecase.PatchFormatMerge, tc.patch)
+				require.ErrorIs(t, err, tc.err)
+				if tc.field != "" {
+					var validationErr *entity.ValidationError
+					require.ErrorAs(t, err, &validationErr)
+					require.Equal(t, tc.field, validationErr.Field)
+				}
+			})
+		}
+	})
+
+	t.Run("JSON patch", func(t *testing.T) {
+		current, err := usecase.NewGetProductUseCase(repository).Execute(context.Background(), created.ID)
+		require.Nil(t, err)
+
+		output, err := patch(usecase.PatchFormatJSON, `[
+			{"op": "test", "path": "/price", "value": 120},
+			{"op": "replace", "path": "/name", "value": "Renamed"},
+			{"op": "add", "path": "/description", "value": "new description"}
+		]`)
+		require.Nil(t, err)
+		require.Equal(t, "Renamed", output.Name)
+		require.Equal(t, "new description", output.Description)
+		require.Equal(t, current.Version+1, output.Version)
+	})
+
+	t.Run("JSON patch that cannot be applied", func(t *testing.T) {
+		_, err := patch(usecase.PatchFormatJSON, `[{"op": "test", "path": "/price", "value": 1}]`)
+		require.ErrorIs(t, err, entity.ErrConflict)
+
+		_, err = patch(usecase.PatchFormatJSON, `[{"op": "remove", "path": "/color"}]`)
+		require.ErrorIs(t, err, entity.ErrConflict)
+
+		_, err = patch(usecase.PatchFormatJSON, `{"op": "remove"}`)
+		require.ErrorIs(t, err, entity.ErrInvalidInput)
+
+		_, err = patch(usecase.PatchFormatJSON, `[{"op": "remove", "path": "/price"}]`)
+		require.ErrorIs(t, err, entity.ErrValidation)
+	})
+
+	t.Run("If-Match", func(t *testing.T) {
+		current, err := usecase.NewGetProductUseCase(repository).Execute(context.Background(), created.ID)
+		require.Nil(t, err)
+
+		_, err = patch(usecase.PatchFormatMerge, `{"price": 1}`, current.Version-1)
+		require.ErrorIs(t, err, entity.ErrPreconditionFailed)
+
+		output, err := patch(usecase.PatchFormatMerge, `{"price": 1}`, current.Version)
+		require.Nil(t, err)
+		require.Equal(t, 1.0, output.Price)
+	})
+
+	t.Run("Missing product", func(t *testing.T) {
+		_, err := usecase.NewPatchProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), usecase.ProductPatchInputDTO{
+			ID:     "non-existent-id",
+			Format: usecase.PatchFormatMerge,
+			Patch:  []byte(`{}`),
+		})
+		require.ErrorIs(t, err, entity.ErrNotFound)
+	})
+}
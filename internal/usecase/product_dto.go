@@ -1,5 +1,13 @@
 package usecase
 
+import (
+	"io"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+)
+
 type ProductInputDTO struct {
 	Name        string  `json:"name"`
 	Description string  `json:"description"`
@@ -7,11 +15,15 @@ type ProductInputDTO struct {
 }
 
 type ProductOutputDTO struct {
-	ID          string  `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Status      string  `json:"status"`
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Price       float64   `json:"price"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+	Version     int       `json:"version"`
+	// DeletedAt is only set for the products in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 type ProductUpdateInputDTO struct {
@@ -19,4 +31,124 @@ type ProductUpdateInputDTO struct {
 	Name        string  `json:"name"`
 	Description string  `json:"description"`
 	Price       float64 `json:"price"`
+	// IfMatch, when not empty, lists the versions the product is expected to be at, as sent in
+	// the If-Match header. The update fails with ErrPreconditionFailed otherwise.
+	IfMatch []int `json:"-"`
+}
+
+type ProductPatchInputDTO struct {
+	ID string
+	// Format is PatchFormatMerge or PatchFormatJSON.
+	Format string
+	Patch  []byte
+	// IfMatch has the same meaning as in ProductUpdateInputDTO.
+	IfMatch []int
+}
+
+func newProductOutputDTO(product *entity.Product) ProductOutputDTO {
+	output := ProductOutputDTO{
+		ID:          product.GetID(),
+		Name:        product.GetName(),
+		Description: product.GetDescription(),
+		Price:       product.GetPrice(),
+		Status:      product.GetStatus(),
+		CreatedAt:   product.GetCreatedAt(),
+		Version:     product.GetVersion(),
+	}
+	if product.IsDeleted() {
+		deletedAt := product.GetDeletedAt()
+		output.DeletedAt = &deletedAt
+	}
+	return output
+}
+
+type ProductAuditEntryOutputDTO struct {
+	ID        string                  `json:"id"`
+	ProductID string                  `json:"product_id"`
+	Actor     string                  `json:"actor"`
+	Operation string                  `json:"operation"`
+	Version   int                     `json:"version"`
+	Changes   []ProductFieldChangeDTO `json:"changes"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+// ProductFieldChangeDTO is the value of a field before and after a change. Before is null for
+// created products and After for products removed for good.
+type ProductFieldChangeDTO struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+func newProductAuditEntryOutputDTO(entry *entity.ProductAuditEntry) ProductAuditEntryOutputDTO {
+	changes := make([]ProductFieldChangeDTO, len(entry.Changes))
+	for i, change := range entry.Changes {
+		changes[i] = ProductFieldChangeDTO(change)
+	}
+	return ProductAuditEntryOutputDTO{
+		ID:        entry.ID,
+		ProductID: entry.ProductID,
+		Actor:     entry.Actor,
+		Operation: entry.Operation,
+		Version:   entry.Version,
+		Changes:   changes,
+		CreatedAt: entry.CreatedAt,
+	}
+}
+
+type ProductImportInputDTO struct {
+	Format string
+	Reader io.Reader
+	DryRun bool
+}
+
+type ProductImportOutputDTO struct {
+	DryRun  bool                        `json:"dry_run"`
+	Total   int                         `json:"total"`
+	Valid   int                         `json:"valid"`
+	Invalid int                         `json:"invalid"`
+	Created int                         `json:"created"`
+	Rows    []ProductImportRowOutputDTO `json:"rows"`
+}
+
+type ProductImportRowOutputDTO struct {
+	Line   int                     `json:"line"`
+	Status string                  `json:"status"`
+	ID     string                  `json:"id,omitempty"`
+	Errors []ProductImportErrorDTO `json:"errors,omitempty"`
+}
+
+type ProductImportErrorDTO struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type ProductExportInputDTO struct {
+	Format string
+	Filter domain.ProductFilter
+	Writer io.Writer
+}
+
+type ProductCursorListInputDTO struct {
+	Filter domain.ProductFilter
+	Sort   string
+	Cursor string
+	Limit  int
+}
+
+type ProductCursorListOutputDTO struct {
+	Products   []ProductOutputDTO
+	NextCursor string
+	PrevCursor string
+}
+
+type ProductBatchGetInputDTO struct {
+	IDs []string `json:"ids"`
+}
+
+type ProductBatchGetOutputDTO struct {
+	// Products are the products found, in the order their ids were requested.
+	Products []ProductOutputDTO `json:"products"`
+	// MissingIDs lists the requested ids without a product, in request order.
+	MissingIDs []string `json:"missing_ids"`
 }
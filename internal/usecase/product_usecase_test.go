@@ -0,0 +1,196 @@
+package usecase_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+	"github.com/stretchr/testify/require"
+)
+
+// discardLogger is given to the use cases that log, keeping the test output clean.
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// transactionManager is given to the use cases that write.
+var transactionManager = database.NewInMemoryTransactionManager()
+
+func TestProductUseCases(t *testing.T) {
+	repository := database.NewInMemoryProductRepository()
+	auditRepository := database.NewInMemoryProductAuditRepository()
+	outboxRepository := database.NewInMemoryProductOutboxRepository()
+
+	created, err := usecase.NewCreateProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), usecase.ProductInputDTO{
+		Name:        "Product 1",
+		Description: "description",
+		Price:       99.99,
+	})
+	require.Nil(t, err)
+	require.NotEmpty(t, created.ID)
+
+	t.Run("Get Product", func(t *testing.T) {
+		output, err := usecase.NewGetProductUseCase(repository).Execute(context.Background(), created.ID)
+		require.Nil(t, err)
+		require.Equal(t, created, output)
+	})
+
+	t.Run("List Products", func(t *testing.T) {
+		output, totalCount, err := usecase.NewListProductsUseCase(repository).Execute(context.Background(), domain.ProductFilter{}, 1, 10, "id")
+		require.Nil(t, err)
+		require.Equal(t, 1, totalCount)
+		require.Equal(t, []usecase.ProductOutputDTO{created}, output)
+	})
+
+	t.Run("Update Product", func(t *testing.T) {
+		output, err := usecase.NewUpdateProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), usecase.ProductUpdateInputDTO{
+			ID:          created.ID,
+			Name:        "Product 1 updated",
+			Description: "new description",
+			Price:       150.00,
+		})
+		require.Nil(t, err)
+		require.Equal(t, "Product 1 updated", output.Name)
+		require.Equal(t, "new description", output.Description)
+		require.Equal(t, 150.00, output.Price)
+	})
+
+	t.Run("Update Product with If-Match", func(t *testing.T) {
+		current, err := usecase.NewGetProductUseCase(repository).Execute(context.Background(), created.ID)
+		require.Nil(t, err)
+
+		_, err = usecase.NewUpdateProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), usecase.ProductUpdateInputDTO{
+			ID:      created.ID,
+			Name:    "Stale update",
+			Price:   1,
+			IfMatch: []int{current.Version - 1},
+		})
+		require.ErrorIs(t, err, entity.ErrPreconditionFailed)
+		require.NotErrorIs(t, err, entity.ErrConflict)
+
+		output, err := usecase.NewUpdateProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), usecase.ProductUpdateInputDTO{
+			ID:          current.ID,
+			Name:        current.Name,
+			Description: current.Description,
+			Price:       current.Price,
+			IfMatch:     []int{current.Version - 1, current.Version},
+		})
+		require.Nil(t, err)
+		require.Equal(t, current.Version+1, output.Version)
+	})
+
+	t.Run("Update Missing Product", func(t *testing.T) {
+		_, err := usecase.NewUpdateProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), usecase.ProductUpdateInputDTO{
+			ID:   "non-existent-id",
+			Name: "Product",
+		})
+		require.EqualError(t, err, "product with id non-existent-id not found")
+	})
+
+	t.Run("Enable and Disable Product", func(t *testing.T) {
+		output, err := usecase.NewEnableProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), created.ID)
+		require.Nil(t, err)
+		require.Equal(t, entity.ENABLED, output.Status)
+
+		stored, err := usecase.NewGetProductUseCase(repository).Execute(context.Background(), created.ID)
+		require.Nil(t, err)
+		require.Equal(t, entity.ENABLED, stored.Status)
+
+		output, err = usecase.NewDisableProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), created.ID)
+		require.Nil(t, err)
+		require.Equal(t, entity.DISABLED, output.Status)
+	})
+
+	t.Run("Enable Product with Zero Price", func(t *testing.T) {
+		free, err := usecase.NewCreateProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), usecase.ProductInputDTO{Name: "Free Product"})
+		require.Nil(t, err)
+
+		_, err = usecase.NewEnableProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), free.ID)
+		require.ErrorIs(t, err, entity.ErrValidation)
+
+		stored, err := usecase.NewGetProductUseCase(repository).Execute(context.Background(), free.ID)
+		require.Nil(t, err)
+		require.Equal(t, entity.DISABLED, stored.Status)
+	})
+
+	t.Run("Canceled context", func(t *testing.T) {
+		before, err := usecase.NewGetProductUseCase(repository).Execute(context.Background(), created.ID)
+		require.Nil(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = usecase.NewUpdateProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(ctx, usecase.ProductUpdateInputDTO{
+			ID:    created.ID,
+			Name:  "Canceled update",
+			Price: 10,
+		})
+		require.ErrorIs(t, err, context.Canceled)
+
+		stored, err := usecase.NewGetProductUseCase(repository).Execute(context.Background(), created.ID)
+		require.Nil(t, err)
+		require.Equal(t, before, stored)
+	})
+
+	t.Run("Delete Product", func(t *testing.T) {
+		err := usecase.NewDeleteProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), created.ID, false)
+		require.Nil(t, err)
+
+		_, err = usecase.NewGetProductUseCase(repository).Execute(context.Background(), created.ID)
+		require.EqualError(t, err, "product with id "+created.ID+" not found")
+	})
+
+	t.Run("List Deleted Products", func(t *testing.T) {
+		output, totalCount, err := usecase.NewListDeletedProductsUseCase(repository).Execute(context.Background(), 1, 10)
+		require.Nil(t, err)
+		require.Equal(t, 1, totalCount)
+		require.Equal(t, created.ID, output[0].ID)
+		require.NotNil(t, output[0].DeletedAt)
+	})
+
+	t.Run("Restore Product", func(t *testing.T) {
+		output, err := usecase.NewRestoreProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), created.ID)
+		require.Nil(t, err)
+		require.Nil(t, output.DeletedAt)
+
+		_, err = usecase.NewGetProductUseCase(repository).Execute(context.Background(), created.ID)
+		require.Nil(t, err)
+
+		_, err = usecase.NewRestoreProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), created.ID)
+		require.ErrorIs(t, err, entity.ErrConflict)
+	})
+
+	t.Run("Purge Deleted Products", func(t *testing.T) {
+		purge := usecase.NewPurgeDeletedProductsUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger)
+		err := usecase.NewDeleteProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), created.ID, false)
+		require.Nil(t, err)
+
+		purged, err := purge.Execute(context.Background(), time.Hour)
+		require.Nil(t, err)
+		require.Equal(t, 0, purged)
+
+		purged, err = purge.Execute(context.Background(), 0)
+		require.Nil(t, err)
+		require.Equal(t, 1, purged)
+
+		_, err = purge.Execute(context.Background(), -time.Hour)
+		require.ErrorIs(t, err, entity.ErrInvalidInput)
+	})
+
+	t.Run("Delete Product Permanently", func(t *testing.T) {
+		product, err := usecase.NewCreateProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), usecase.ProductInputDTO{
+			Name:  "Product 2",
+			Price: 10,
+		})
+		require.Nil(t, err)
+
+		err = usecase.NewDeleteProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), product.ID, true)
+		require.Nil(t, err)
+
+		_, err = usecase.NewRestoreProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(context.Background(), product.ID)
+		require.ErrorIs(t, err, entity.ErrNotFound)
+	})
+}
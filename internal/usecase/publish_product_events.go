@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type PublishProductEventsUseCase struct {
+	OutboxRepository domain.OutboxRepositoryInterface
+	Publisher        domain.EventPublisher
+	// MaxAttempts is the number of failed attempts after which an event is set aside as dead.
+	MaxAttempts int
+	// Lease keeps claimed events from being claimed again while they are published. It must be
+	// longer than the time taken to publish a batch.
+	Lease  time.Duration
+	Logger *slog.Logger
+}
+
+func NewPublishProductEventsUseCase(
+	outboxRepository domain.OutboxRepositoryInterface,
+	publisher domain.EventPublisher,
+	maxAttempts int,
+	lease time.Duration,
+	logger *slog.Logger,
+) *PublishProductEventsUseCase {
+	return &PublishProductEventsUseCase{
+		OutboxRepository: outboxRepository,
+		Publisher:        publisher,
+		MaxAttempts:      maxAttempts,
+		Lease:            lease,
+		Logger:           logger,
+	}
+}
+
+// Execute publishes up to limit pending events of the outbox, in the order they were raised, and
+// returns how many were published. It stops at the first event that cannot be published, which
+// stays pending along with the ones after it, so that consumers do not see events out of order.
+// Once an event has failed MaxAttempts times it becomes dead and is skipped, so that it does not
+// hold back the events after it forever. Events are delivered at least once: an event published
+// right before the outbox could be updated is published again by a later call.
+//
+// The events are claimed rather than locked, so no transaction stays open while they are
+// published, and every outcome is recorded by a statement of its own: a publisher cannot undo the
+// failed attempts, whatever it does with the database.
+func (u *PublishProductEventsUseCase) Execute(ctx context.Context, limit int) (_ int, err error) {
+	ctx, span := tracer.Start(ctx, "PublishProductEventsUseCase.Execute", trace.WithAttributes(attribute.Int("limit", limit)))
+	defer func() { endSpan(span, err) }()
+
+	if limit <= 0 {
+		return 0, fmt.Errorf("%w: limit must be greater than zero", entity.ErrInvalidInput)
+	}
+
+	events, err := u.OutboxRepository.ClaimPending(ctx, time.Now(), u.Lease, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	// The outcomes are recorded even when ctx ends meanwhile, so that a stopping relay does not
+	// leave events claimed until their lease expires
+	recordCtx := context.WithoutCancel(ctx)
+	var published, unattempted []string
+	var publishErr, recordErr error
+	for i, event := range events {
+		err := u.Publisher.Publish(ctx, event)
+		if err == nil {
+			published = append(published, event.ID)
+			continue
+		}
+		if ctx.Err() != nil {
+			// Interrupted rather than failed, so the attempt does not count
+			unattempted = eventIDs(events[i:])
+			publishErr = ctx.Err()
+			break
+		}
+
+		err = fmt.Errorf("publishing event %s: %w", event.ID, err)
+		dead, markErr := u.OutboxRepository.MarkFailed(recordCtx, event.ID, err.Error(), u.MaxAttempts)
+		if markErr != nil {
+			unattempted = eventIDs(events[i:])
+			publishErr, recordErr = err, markErr
+			break
+		}
+		if !dead {
+			unattempted = eventIDs(events[i+1:])
+			publishErr = err
+			break
+		}
+		u.Logger.ErrorContext(ctx, "product event is dead", "event_id", event.ID,
+			"event_type", event.Type, "product_id", event.ProductID, "attempts", u.MaxAttempts, "error", err)
+	}
+	recordErr = errors.Join(recordErr,
+		u.OutboxRepository.MarkPublished(recordCtx, published...),
+		u.OutboxRepository.Release(recordCtx, unattempted...))
+	if recordErr != nil {
+		return 0, recordErr
+	}
+
+	span.SetAttributes(attribute.Int("published", len(published)))
+	if len(published) > 0 {
+		u.Logger.DebugContext(ctx, "product events published", "count", len(published))
+	}
+	return len(published), publishErr
+}
+
+// eventIDs returns the ids of events.
+func eventIDs(events []*entity.ProductEvent) []string {
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return ids
+}
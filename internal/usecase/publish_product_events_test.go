@@ -0,0 +1,137 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/infra/events"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+	"github.com/stretchr/testify/require"
+)
+
+// failingPublisher fails to publish the events of the given type.
+type failingPublisher struct {
+	events.ChannelPublisher
+	failing string
+}
+
+func (p *failingPublisher) Publish(ctx context.Context, event *entity.ProductEvent) error {
+	if event.Type == p.failing {
+		return errors.New("broker unavailable")
+	}
+	return p.ChannelPublisher.Publish(ctx, event)
+}
+
+func TestPublishProductEventsUseCase(t *testing.T) {
+	repository := database.NewInMemoryProductRepository()
+	auditRepository := database.NewInMemoryProductAuditRepository()
+	outboxRepository := database.NewInMemoryProductOutboxRepository()
+	ctx := context.Background()
+
+	created, err := usecase.NewCreateProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(ctx, usecase.ProductInputDTO{
+		Name:  "Product 1",
+		Price: 10,
+	})
+	require.NoError(t, err)
+	_, err = usecase.NewUpdateProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(ctx, usecase.ProductUpdateInputDTO{
+		ID:    created.ID,
+		Name:  "Product 1",
+		Price: 12,
+	})
+	require.NoError(t, err)
+	_, err = usecase.NewEnableProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(ctx, created.ID)
+	require.NoError(t, err)
+	require.NoError(t, usecase.NewDeleteProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(ctx, created.ID, false))
+	_, err = usecase.NewRestoreProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(ctx, created.ID)
+	require.NoError(t, err)
+
+	publisher := &failingPublisher{ChannelPublisher: *events.NewChannelPublisher(10), failing: entity.ProductEnabled}
+	publish := usecase.NewPublishProductEventsUseCase(outboxRepository, publisher, 3, time.Minute, discardLogger)
+
+	// Publication stops at the first failure, keeping the events in order
+	published, err := publish.Execute(ctx, 10)
+	require.ErrorContains(t, err, "broker unavailable")
+	require.Equal(t, 2, published)
+	require.Equal(t, entity.ProductCreated, (<-publisher.C).Type)
+	priceChanged := <-publisher.C
+	require.Equal(t, entity.PriceChanged, priceChanged.Type)
+	require.Equal(t, created.ID, priceChanged.ProductID)
+	require.Equal(t, 2, priceChanged.Version)
+
+	publisher.failing = ""
+	published, err = publish.Execute(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, 2, published)
+	enabled, deleted := <-publisher.C, <-publisher.C
+	require.Equal(t, entity.ProductEnabled, enabled.Type)
+	require.Equal(t, 3, enabled.Version)
+	require.Equal(t, entity.ProductDeleted, deleted.Type)
+	require.Equal(t, 4, deleted.Version)
+
+	published, err = publish.Execute(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, published)
+	restored := <-publisher.C
+	require.Equal(t, entity.ProductRestored, restored.Type)
+	require.Equal(t, 5, restored.Version)
+
+	published, err = publish.Execute(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, 0, published)
+
+	_, err = publish.Execute(ctx, 0)
+	require.ErrorIs(t, err, entity.ErrInvalidInput)
+}
+
+func TestPublishProductEventsUseCaseDeadEvents(t *testing.T) {
+	outboxRepository := database.NewInMemoryProductOutboxRepository()
+	ctx := context.Background()
+
+	product, err := entity.NewProduct("Product 1", "", 10)
+	require.NoError(t, err)
+	require.NoError(t, product.ChangePrice(12))
+	require.NoError(t, outboxRepository.Append(ctx, product.PullEvents()...))
+
+	publisher := &failingPublisher{ChannelPublisher: *events.NewChannelPublisher(10), failing: entity.ProductCreated}
+	publish := usecase.NewPublishProductEventsUseCase(outboxRepository, publisher, 2, time.Minute, discardLogger)
+
+	published, err := publish.Execute(ctx, 10)
+	require.ErrorContains(t, err, "broker unavailable")
+	require.Equal(t, 0, published)
+
+	// An event that failed every attempt no longer holds back the ones after it
+	published, err = publish.Execute(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, published)
+	require.Equal(t, entity.PriceChanged, (<-publisher.C).Type)
+
+	pending, err := outboxRepository.ClaimPending(ctx, time.Now(), time.Minute, 10)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+}
+
+func TestPublishProductEventsUseCaseInterrupted(t *testing.T) {
+	outboxRepository := database.NewInMemoryProductOutboxRepository()
+	product, err := entity.NewProduct("Product 1", "", 10)
+	require.NoError(t, err)
+	require.NoError(t, product.ChangePrice(12))
+	require.NoError(t, outboxRepository.Append(context.Background(), product.PullEvents()...))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	publisher := events.PublisherFunc(func(ctx context.Context, event *entity.ProductEvent) error {
+		cancel()
+		return ctx.Err()
+	})
+	publish := usecase.NewPublishProductEventsUseCase(outboxRepository, publisher, 1, time.Hour, discardLogger)
+	_, err = publish.Execute(ctx, 10)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// The events are neither failed nor left claimed by a relay that stops
+	pending, err := outboxRepository.ClaimPending(context.Background(), time.Now(), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type PurgeDeletedProductsUseCase struct {
+	ProductRepository  domain.ProductRepositoryInterface
+	AuditRepository    domain.ProductAuditRepositoryInterface
+	OutboxRepository   domain.OutboxRepositoryInterface
+	TransactionManager domain.TransactionManager
+	Logger             *slog.Logger
+}
+
+func NewPurgeDeletedProductsUseCase(
+	productRepository domain.ProductRepositoryInterface,
+	auditRepository domain.ProductAuditRepositoryInterface,
+	outboxRepository domain.OutboxRepositoryInterface,
+	transactionManager domain.TransactionManager,
+	logger *slog.Logger,
+) *PurgeDeletedProductsUseCase {
+	return &PurgeDeletedProductsUseCase{
+		ProductRepository:  productRepository,
+		AuditRepository:    auditRepository,
+		OutboxRepository:   outboxRepository,
+		TransactionManager: transactionManager,
+		Logger:             logger,
+	}
+}
+
+// Execute permanently removes the products that have been in the trash for longer than
+// retention and returns how many were removed.
+func (u *PurgeDeletedProductsUseCase) Execute(ctx context.Context, retention time.Duration) (_ int, err error) {
+	ctx, span := tracer.Start(ctx, "PurgeDeletedProductsUseCase.Execute", trace.WithAttributes(
+		attribute.String("retention", retention.String())))
+	defer func() { endSpan(span, err) }()
+
+	if retention < 0 {
+		return 0, fmt.Errorf("%w: retention cannot be negative", entity.ErrInvalidInput)
+	}
+
+	var purged []*entity.Product
+	err = u.TransactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		purged, err = u.ProductRepository.PurgeDeleted(ctx, time.Now().Add(-retention))
+		if err != nil || len(purged) == 0 {
+			return err
+		}
+		entries := make([]*entity.ProductAuditEntry, len(purged))
+		for i, product := range purged {
+			entries[i] = newAuditEntry(ctx, entity.AuditPurge, product, entity.DiffProducts(product, nil))
+			product.RecordDeleted(true)
+		}
+		if err := u.AuditRepository.Append(ctx, entries...); err != nil {
+			return err
+		}
+		return u.OutboxRepository.Append(ctx, pullEvents(purged...)...)
+	})
+	if err != nil {
+		return 0, err
+	}
+	span.SetAttributes(attribute.Int("purged", len(purged)))
+	if len(purged) > 0 {
+		u.Logger.InfoContext(ctx, "deleted products purged", "count", len(purged), "retention", retention.String())
+	}
+	return len(purged), nil
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type RestoreProductUseCase struct {
+	ProductRepository  domain.ProductRepositoryInterface
+	AuditRepository    domain.ProductAuditRepositoryInterface
+	OutboxRepository   domain.OutboxRepositoryInterface
+	TransactionManager domain.TransactionManager
+	Logger             *slog.Logger
+}
+
+func NewRestoreProductUseCase(
+	productRepository domain.ProductRepositoryInterface,
+	auditRepository domain.ProductAuditRepositoryInterface,
+	outboxRepository domain.OutboxRepositoryInterface,
+	transactionManager domain.TransactionManager,
+	logger *slog.Logger,
+) *RestoreProductUseCase {
+	return &RestoreProductUseCase{
+		ProductRepository:  productRepository,
+		AuditRepository:    auditRepository,
+		OutboxRepository:   outboxRepository,
+		TransactionManager: transactionManager,
+		Logger:             logger,
+	}
+}
+
+// Execute takes a product out of the trash, undoing a soft delete.
+func (u *RestoreProductUseCase) Execute(ctx context.Context, id string) (_ ProductOutputDTO, err error) {
+	ctx, span := tracer.Start(ctx, "RestoreProductUseCase.Execute", trace.WithAttributes(attribute.String("product.id", id)))
+	defer func() { endSpan(span, err) }()
+
+	var product *entity.Product
+	err = u.TransactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		product, err = u.ProductRepository.Restore(ctx, id)
+		if err != nil {
+			return err
+		}
+		product.RecordRestored()
+		if err := u.AuditRepository.Append(ctx, newAuditEntry(ctx, entity.AuditRestore, product, trashChange(false))); err != nil {
+			return err
+		}
+		return u.OutboxRepository.Append(ctx, product.PullEvents()...)
+	})
+	if err != nil {
+		return ProductOutputDTO{}, err
+	}
+	u.Logger.InfoContext(ctx, "product restored", "product_id", product.GetID())
+
+	return newProductOutputDTO(product), nil
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type RetryWebhookDeliveryUseCase struct {
+	WebhookRepository domain.WebhookRepositoryInterface
+	Logger            *slog.Logger
+}
+
+func NewRetryWebhookDeliveryUseCase(webhookRepository domain.WebhookRepositoryInterface, logger *slog.Logger) *RetryWebhookDeliveryUseCase {
+	return &RetryWebhookDeliveryUseCase{
+		WebhookRepository: webhookRepository,
+		Logger:            logger,
+	}
+}
+
+// Execute takes a dead delivery of the webhook out of the dead letters, to be sent again right
+// away with a new series of attempts. Deliveries that are not dead are reported as a conflict.
+func (u *RetryWebhookDeliveryUseCase) Execute(ctx context.Context, webhookID, deliveryID string) (_ WebhookDeliveryOutputDTO, err error) {
+	ctx, span := tracer.Start(ctx, "RetryWebhookDeliveryUseCase.Execute", trace.WithAttributes(
+		attribute.String("webhook.id", webhookID), attribute.String("delivery.id", deliveryID)))
+	defer func() { endSpan(span, err) }()
+
+	delivery, err := u.WebhookRepository.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return WebhookDeliveryOutputDTO{}, err
+	}
+	if delivery.WebhookID != webhookID {
+		return WebhookDeliveryOutputDTO{}, fmt.Errorf("%w: delivery with id %s not found", entity.ErrNotFound, deliveryID)
+	}
+	if delivery.Status != entity.DeliveryDead {
+		return WebhookDeliveryOutputDTO{}, fmt.Errorf("%w: delivery with id %s is %s, only dead deliveries can be retried",
+			entity.ErrConflict, deliveryID, delivery.Status)
+	}
+
+	delivery.Retry(time.Now().UTC())
+	if err = u.WebhookRepository.UpdateDelivery(ctx, delivery); err != nil {
+		return WebhookDeliveryOutputDTO{}, err
+	}
+	u.Logger.InfoContext(ctx, "webhook delivery retried", "webhook_id", webhookID, "delivery_id", deliveryID)
+
+	return newWebhookDeliveryOutputDTO(delivery), nil
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer starts a span for every use case execution, as a child of the span of the request.
+var tracer = otel.Tracer("github.com/HaroldoFV/product-service/internal/usecase")
+
+// endSpan records err, if any, and ends span. Domain errors such as a missing product are
+// expected outcomes, so only other errors mark the span as failed.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		if !isDomainError(err) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
+
+func isDomainError(err error) bool {
+	return errors.Is(err, entity.ErrNotFound) ||
+		errors.Is(err, entity.ErrConflict) ||
+		errors.Is(err, entity.ErrValidation) ||
+		errors.Is(err, entity.ErrInvalidInput) ||
+		errors.Is(err, entity.ErrPreconditionFailed)
+}
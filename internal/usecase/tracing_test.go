@@ -0,0 +1,55 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestUseCaseSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	repository := database.NewInMemoryProductRepository()
+	auditRepository := database.NewInMemoryProductAuditRepository()
+	outboxRepository := database.NewInMemoryProductOutboxRepository()
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+
+	created, err := usecase.NewCreateProductUseCase(repository, auditRepository, outboxRepository, transactionManager, discardLogger).Execute(ctx, usecase.ProductInputDTO{
+		Name:  "Product 1",
+		Price: 10,
+	})
+	require.NoError(t, err)
+	_, err = usecase.NewGetProductUseCase(repository).Execute(ctx, "missing")
+	require.Error(t, err)
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = usecase.NewGetProductUseCase(repository).Execute(canceled, created.ID)
+	require.Error(t, err)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+
+	require.Equal(t, "CreateProductUseCase.Execute", spans[0].Name())
+	require.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	// A missing product is an expected outcome, recorded without failing the span
+	require.Equal(t, "GetProductUseCase.Execute", spans[1].Name())
+	require.Equal(t, codes.Unset, spans[1].Status().Code)
+	require.Len(t, spans[1].Events(), 1)
+
+	require.Equal(t, "GetProductUseCase.Execute", spans[2].Name())
+	require.Equal(t, codes.Error, spans[2].Status().Code)
+}
@@ -1,27 +1,58 @@
 package usecase
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
 	"github.com/HaroldoFV/product-service/internal/domain"
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type UpdateProductUseCase struct {
-	ProductRepository domain.ProductRepositoryInterface
+	ProductRepository  domain.ProductRepositoryInterface
+	AuditRepository    domain.ProductAuditRepositoryInterface
+	OutboxRepository   domain.OutboxRepositoryInterface
+	TransactionManager domain.TransactionManager
+	Logger             *slog.Logger
 }
 
 func NewUpdateProductUseCase(
 	productRepository domain.ProductRepositoryInterface,
+	auditRepository domain.ProductAuditRepositoryInterface,
+	outboxRepository domain.OutboxRepositoryInterface,
+	transactionManager domain.TransactionManager,
+	logger *slog.Logger,
 ) *UpdateProductUseCase {
 	return &UpdateProductUseCase{
-		ProductRepository: productRepository,
+		ProductRepository:  productRepository,
+		AuditRepository:    auditRepository,
+		OutboxRepository:   outboxRepository,
+		TransactionManager: transactionManager,
+		Logger:             logger,
 	}
 }
 
-func (u *UpdateProductUseCase) Execute(input ProductUpdateInputDTO) (ProductOutputDTO, error) {
-	product, err := u.ProductRepository.GetByID(input.ID)
+// Execute replaces the name, description and price of the product. When input.IfMatch is set the
+// product must be at one of those versions, both when it is read and when it is written; without
+// it, a product modified between the two is still reported as a conflict instead of being overwritten.
+func (u *UpdateProductUseCase) Execute(ctx context.Context, input ProductUpdateInputDTO) (_ ProductOutputDTO, err error) {
+	ctx, span := tracer.Start(ctx, "UpdateProductUseCase.Execute", trace.WithAttributes(attribute.String("product.id", input.ID)))
+	defer func() { endSpan(span, err) }()
+
+	product, err := u.ProductRepository.GetByID(ctx, input.ID)
 	if err != nil {
 		return ProductOutputDTO{}, err
 	}
+	if err = checkIfMatch(product, input.IfMatch); err != nil {
+		return ProductOutputDTO{}, err
+	}
 
+	before := *product
 	err = product.Update(input.Name, input.Description)
 	if err != nil {
 		return ProductOutputDTO{}, err
@@ -32,17 +63,39 @@ func (u *UpdateProductUseCase) Execute(input ProductUpdateInputDTO) (ProductOutp
 		return ProductOutputDTO{}, err
 	}
 
-	err = u.ProductRepository.Update(product)
+	err = u.TransactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.ProductRepository.Update(ctx, product); err != nil {
+			return versionConflictError(err, input.IfMatch)
+		}
+		if err := u.AuditRepository.Append(ctx, newAuditEntry(ctx, entity.AuditUpdate, product, entity.DiffProducts(&before, product))); err != nil {
+			return err
+		}
+		return u.OutboxRepository.Append(ctx, product.PullEvents()...)
+	})
 	if err != nil {
 		return ProductOutputDTO{}, err
 	}
+	u.Logger.InfoContext(ctx, "product updated", "product_id", product.GetID())
+
+	return newProductOutputDTO(product), nil
+}
+
+// checkIfMatch returns ErrPreconditionFailed unless ifMatch is empty or lists the version of product.
+func checkIfMatch(product *entity.Product, ifMatch []int) error {
+	if len(ifMatch) > 0 && !slices.Contains(ifMatch, product.GetVersion()) {
+		return fmt.Errorf("%w: product with id %s is at version %d",
+			entity.ErrPreconditionFailed, product.GetID(), product.GetVersion())
+	}
+	return nil
+}
 
-	dto := ProductOutputDTO{
-		ID:          product.GetID(),
-		Name:        product.GetName(),
-		Description: product.GetDescription(),
-		Price:       product.GetPrice(),
-		Status:      product.GetStatus(),
+// versionConflictError turns a version conflict reported by the repository into
+// ErrPreconditionFailed when the client sent If-Match, since its precondition no longer holds.
+// Other errors are returned unchanged.
+func versionConflictError(err error, ifMatch []int) error {
+	var conflictErr *entity.ProductVersionConflictError
+	if len(ifMatch) > 0 && errors.As(err, &conflictErr) {
+		return fmt.Errorf("%w: %s", entity.ErrPreconditionFailed, err)
 	}
-	return dto, nil
+	return err
 }
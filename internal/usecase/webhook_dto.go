@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+)
+
+type WebhookInputDTO struct {
+	URL string `json:"url"`
+	// Events are the types of the events delivered, every type when empty.
+	Events []string `json:"events"`
+	// Secret signs the deliveries. A random one is generated when empty.
+	Secret string `json:"secret"`
+}
+
+type WebhookOutputDTO struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	// Secret is only returned when the webhook is created.
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookDeliveryOutputDTO struct {
+	ID        string `json:"id"`
+	WebhookID string `json:"webhook_id"`
+	EventID   string `json:"event_id"`
+	EventType string `json:"event_type"`
+	ProductID string `json:"product_id"`
+	Status    string `json:"status"`
+	Attempts  int    `json:"attempts"`
+	// NextAttemptAt is only set while the delivery is pending.
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+func newWebhookOutputDTO(webhook *entity.Webhook) WebhookOutputDTO {
+	events := webhook.Events
+	if events == nil {
+		events = []string{}
+	}
+	return WebhookOutputDTO{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    events,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+func newWebhookDeliveryOutputDTO(delivery *entity.WebhookDelivery) WebhookDeliveryOutputDTO {
+	output := WebhookDeliveryOutputDTO{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		EventID:        delivery.Event.ID,
+		EventType:      delivery.Event.Type,
+		ProductID:      delivery.Event.ProductID,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.Status == entity.DeliveryPending {
+		nextAttemptAt := delivery.NextAttemptAt
+		output.NextAttemptAt = &nextAttemptAt
+	}
+	if !delivery.DeliveredAt.IsZero() {
+		deliveredAt := delivery.DeliveredAt
+		output.DeliveredAt = &deliveredAt
+	}
+	return output
+}
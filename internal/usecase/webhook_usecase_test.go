@@ -0,0 +1,206 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HaroldoFV/product-service/internal/domain/entity"
+	"github.com/HaroldoFV/product-service/internal/infra/database"
+	"github.com/HaroldoFV/product-service/internal/infra/events"
+	"github.com/HaroldoFV/product-service/internal/usecase"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateWebhookUseCase(t *testing.T) {
+	repository := database.NewInMemoryWebhookRepository()
+	ctx := context.Background()
+	create := usecase.NewCreateWebhookUseCase(repository, discardLogger)
+
+	created, err := create.Execute(ctx, usecase.WebhookInputDTO{URL: "https://example.com/hooks"})
+	require.NoError(t, err)
+	require.Len(t, created.Secret, 64, "a secret is generated when none is given")
+	require.Equal(t, []string{}, created.Events)
+
+	_, err = create.Execute(ctx, usecase.WebhookInputDTO{URL: "example.com"})
+	require.ErrorIs(t, err, entity.ErrValidation)
+	_, err = create.Execute(ctx, usecase.WebhookInputDTO{URL: "https://example.com", Secret: "short"})
+	require.ErrorIs(t, err, entity.ErrValidation)
+
+	// The secret is only returned on creation
+	found, err := usecase.NewGetWebhookUseCase(repository).Execute(ctx, created.ID)
+	require.NoError(t, err)
+	require.Empty(t, found.Secret)
+	require.Equal(t, created.URL, found.URL)
+	webhooks, err := usecase.NewListWebhooksUseCase(repository).Execute(ctx)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	require.Empty(t, webhooks[0].Secret)
+
+	require.NoError(t, usecase.NewDeleteWebhookUseCase(repository, discardLogger).Execute(ctx, created.ID))
+	_, err = usecase.NewGetWebhookUseCase(repository).Execute(ctx, created.ID)
+	require.ErrorIs(t, err, entity.ErrNotFound)
+	require.ErrorIs(t, usecase.NewDeleteWebhookUseCase(repository, discardLogger).Execute(ctx, created.ID), entity.ErrNotFound)
+}
+
+// failingDeliveriesRepository cannot store webhook deliveries.
+type failingDeliveriesRepository struct {
+	*database.InMemoryWebhookRepository
+}
+
+func (r failingDeliveriesRepository) CreateDeliveries(ctx context.Context, deliveries ...*entity.WebhookDelivery) error {
+	return errors.New("database unavailable")
+}
+
+func TestEnqueueWebhookDeliveriesFailuresAreCounted(t *testing.T) {
+	outboxRepository := database.NewInMemoryProductOutboxRepository()
+	ctx := context.Background()
+	product, err := entity.NewProduct("Product", "description", 10)
+	require.NoError(t, err)
+	require.NoError(t, outboxRepository.Append(ctx, product.PullEvents()...))
+
+	enqueue := usecase.NewEnqueueWebhookDeliveriesUseCase(failingDeliveriesRepository{database.NewInMemoryWebhookRepository()}, transactionManager)
+	publisher := events.MultiPublisher{events.NewChannelPublisher(10), events.PublisherFunc(enqueue.Execute)}
+	publish := usecase.NewPublishProductEventsUseCase(outboxRepository, publisher, 2, time.Minute, discardLogger)
+
+	_, err = publish.Execute(ctx, 10)
+	require.ErrorContains(t, err, "database unavailable")
+	pending, err := outboxRepository.ClaimPending(ctx, time.Now(), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1, "the event stays pending after its first failure")
+	require.NoError(t, outboxRepository.Release(ctx, pending[0].ID))
+
+	published, err := publish.Execute(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, 0, published)
+	pending, err = outboxRepository.ClaimPending(ctx, time.Now(), time.Minute, 10)
+	require.NoError(t, err)
+	require.Empty(t, pending, "the event is dead after its last attempt")
+}
+
+func TestEnqueueWebhookDeliveriesUseCase(t *testing.T) {
+	repository := database.NewInMemoryWebhookRepository()
+	ctx := context.Background()
+	create := usecase.NewCreateWebhookUseCase(repository, discardLogger)
+	all, err := create.Execute(ctx, usecase.WebhookInputDTO{URL: "https://example.com/all"})
+	require.NoError(t, err)
+	prices, err := create.Execute(ctx, usecase.WebhookInputDTO{URL: "https://example.com/prices", Events: []string{entity.PriceChanged}})
+	require.NoError(t, err)
+
+	product, err := entity.NewProduct("Product", "description", 10)
+	require.NoError(t, err)
+	require.NoError(t, product.ChangePrice(12))
+	enqueue := usecase.NewEnqueueWebhookDeliveriesUseCase(repository, transactionManager)
+	for _, event := range product.PullEvents() {
+		require.NoError(t, enqueue.Execute(ctx, event))
+	}
+
+	list := usecase.NewListWebhookDeliveriesUseCase(repository)
+	deliveries, totalCount, err := list.Execute(ctx, all.ID, "", 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, 2, totalCount)
+	require.Equal(t, entity.PriceChanged, deliveries[0].EventType, "most recent first")
+	require.Equal(t, entity.ProductCreated, deliveries[1].EventType)
+	require.NotNil(t, deliveries[0].NextAttemptAt)
+
+	deliveries, totalCount, err = list.Execute(ctx, prices.ID, entity.DeliveryPending, 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, totalCount)
+	require.Equal(t, entity.PriceChanged, deliveries[0].EventType)
+	require.Equal(t, product.GetID(), deliveries[0].ProductID)
+
+	_, _, err = list.Execute(ctx, prices.ID, "lost", 1, 10)
+	require.ErrorIs(t, err, entity.ErrInvalidInput)
+	_, _, err = list.Execute(ctx, "missing", "", 1, 10)
+	require.ErrorIs(t, err, entity.ErrNotFound)
+}
+
+// statusSender answers every delivery with the next status of its list, failing to connect on zero.
+type statusSender struct {
+	statuses []int
+	sent     []string
+}
+
+func (s *statusSender) Send(ctx context.Context, webhook *entity.Webhook, delivery *entity.WebhookDelivery) (int, error) {
+	s.sent = append(s.sent, delivery.ID)
+	status := s.statuses[0]
+	s.statuses = s.statuses[1:]
+	if status == 0 {
+		return 0, errors.New("connection refused")
+	}
+	return status, nil
+}
+
+func TestDispatchWebhookDeliveriesUseCase(t *testing.T) {
+	repository := database.NewInMemoryWebhookRepository()
+	ctx := context.Background()
+	created, err := usecase.NewCreateWebhookUseCase(repository, discardLogger).Execute(ctx, usecase.WebhookInputDTO{URL: "https://example.com/hooks"})
+	require.NoError(t, err)
+	product, err := entity.NewProduct("Product", "description", 10)
+	require.NoError(t, err)
+	require.NoError(t, usecase.NewEnqueueWebhookDeliveriesUseCase(repository, transactionManager).Execute(ctx, product.PullEvents()[0]))
+
+	sender := &statusSender{statuses: []int{500, 0, 200}}
+	// Without delays every failed attempt is due again right away
+	policy := usecase.WebhookRetryPolicy{MaxAttempts: 2}
+	dispatch := usecase.NewDispatchWebhookDeliveriesUseCase(repository, sender, policy, 0, discardLogger)
+	list := usecase.NewListWebhookDeliveriesUseCase(repository)
+
+	attempted, err := dispatch.Execute(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, attempted)
+	deliveries, _, err := list.Execute(ctx, created.ID, "", 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, entity.DeliveryPending, deliveries[0].Status)
+	require.Equal(t, 1, deliveries[0].Attempts)
+	require.Equal(t, 500, deliveries[0].LastStatusCode)
+	require.Equal(t, "unexpected status 500", deliveries[0].LastError)
+
+	// The last attempt moves the delivery to the dead letters, where it is no longer attempted
+	attempted, err = dispatch.Execute(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, attempted)
+	dead, totalCount, err := list.Execute(ctx, "", entity.DeliveryDead, 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, totalCount)
+	require.Equal(t, 2, dead[0].Attempts)
+	require.Equal(t, "connection refused", dead[0].LastError)
+	require.Nil(t, dead[0].NextAttemptAt)
+	attempted, err = dispatch.Execute(ctx, 10)
+	require.NoError(t, err)
+	require.Zero(t, attempted)
+
+	retry := usecase.NewRetryWebhookDeliveryUseCase(repository, discardLogger)
+	_, err = retry.Execute(ctx, "missing", dead[0].ID)
+	require.ErrorIs(t, err, entity.ErrNotFound)
+	retried, err := retry.Execute(ctx, created.ID, dead[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.DeliveryPending, retried.Status)
+	require.Zero(t, retried.Attempts)
+
+	attempted, err = dispatch.Execute(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, attempted)
+	deliveries, _, err = list.Execute(ctx, created.ID, "", 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, entity.DeliveryDelivered, deliveries[0].Status)
+	require.NotNil(t, deliveries[0].DeliveredAt)
+	require.Equal(t, []string{dead[0].ID, dead[0].ID, dead[0].ID}, sender.sent)
+
+	// Only dead deliveries can be retried
+	_, err = retry.Execute(ctx, created.ID, dead[0].ID)
+	require.ErrorIs(t, err, entity.ErrConflict)
+
+	_, err = dispatch.Execute(ctx, 0)
+	require.ErrorIs(t, err, entity.ErrInvalidInput)
+}
+
+func TestWebhookRetryPolicy(t *testing.T) {
+	policy := usecase.WebhookRetryPolicy{MaxAttempts: 8, BaseDelay: 30 * time.Second, MaxDelay: 5 * time.Minute}
+	require.Equal(t, policy.BaseDelay, policy.Delay(1))
+	require.Equal(t, 2*policy.BaseDelay, policy.Delay(2))
+	require.Equal(t, 8*policy.BaseDelay, policy.Delay(4))
+	require.Equal(t, policy.MaxDelay, policy.Delay(5))
+	require.Equal(t, policy.MaxDelay, policy.Delay(20))
+}
@@ -0,0 +1,9 @@
+//go:build tools
+
+// Package tools tracks the code generators run by the Makefile, so that their versions are
+// pinned in go.mod.
+package tools
+
+import (
+	_ "github.com/99designs/gqlgen"
+)